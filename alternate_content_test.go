@@ -0,0 +1,22 @@
+package docx
+
+import "testing"
+
+func TestDocument_ReplaceAlternateContent(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/alternate_content.xml"))}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := doc.ReplaceAll(PlaceholderMap{"title": "Report"}); err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+
+	// the text of the choice and the fallback must be equal, regardless of which one Word renders
+	expected := "Title: Report" + "Report in a shape" + "Report in a shape"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
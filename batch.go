@@ -0,0 +1,109 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// BatchError is returned if rendering failed for one or more datasets of a batch.
+type BatchError struct {
+	// Errors has the same order as the rendered datasets, successfully rendered datasets have a nil error.
+	Errors []error
+}
+
+// Error implements the error interface and lists all failed datasets.
+func (e *BatchError) Error() string {
+	var failures []string
+	for i, err := range e.Errors {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("dataset %d: %s", i, err))
+		}
+	}
+	return fmt.Sprintf("rendering failed for %d datasets: %s", len(failures), strings.Join(failures, "; "))
+}
+
+// RenderBatch renders the template once for every dataset and returns the resulting docx files.
+// The template is parsed only once, every dataset is rendered into a clone of it (see Document.Clone).
+// Rendering happens concurrently using the given amount of workers, a concurrency < 1 is treated as 1.
+//
+// The results have the same order as the datasets. If a dataset could not be rendered, its result is nil and
+// a *BatchError is returned which holds the errors of the individual datasets.
+func RenderBatch(templatePath string, datasets []PlaceholderMap, concurrency int) ([][]byte, error) {
+	template, err := Open(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	defer template.Close()
+
+	return template.renderBatch(datasets, concurrency)
+}
+
+// RenderMany renders the template once for every override, the placeholders are replaced by the base map merged
+// with the override. Values of the override take precedence, thus the base holds the values shared by all documents
+// and the overrides only the ones which differ. The base is not modified.
+// Rendering happens concurrently, just like RenderBatch, using one worker per CPU.
+//
+// The results have the same order as the overrides. If a document could not be rendered, its result is nil and
+// a *BatchError is returned which holds the errors of the individual documents.
+func RenderMany(templatePath string, base PlaceholderMap, overrides []PlaceholderMap) ([][]byte, error) {
+	datasets := make([]PlaceholderMap, len(overrides))
+	for i, override := range overrides {
+		datasets[i] = make(PlaceholderMap, len(base)+len(override))
+		for key, value := range base {
+			datasets[i][key] = value
+		}
+		for key, value := range override {
+			datasets[i][key] = value
+		}
+	}
+	return RenderBatch(templatePath, datasets, runtime.GOMAXPROCS(0))
+}
+
+// renderBatch renders clones of the document for every dataset using a pool of workers.
+func (d *Document) renderBatch(datasets []PlaceholderMap, concurrency int) ([][]byte, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([][]byte, len(datasets))
+	errs := make([]error, len(datasets))
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = d.Clone().render(datasets[i])
+			}
+		}()
+	}
+	for i := range datasets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, &BatchError{Errors: errs}
+		}
+	}
+	return results, nil
+}
+
+// render replaces all placeholders using the given map and returns the resulting docx.
+func (d *Document) render(placeholderMap PlaceholderMap) ([]byte, error) {
+	if err := d.ReplaceAll(placeholderMap); err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := d.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
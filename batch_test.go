@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderBatch(t *testing.T) {
+	var datasets []PlaceholderMap
+	for i := 0; i < 8; i++ {
+		datasets = append(datasets, PlaceholderMap{
+			"key":           fmt.Sprintf("value-%d", i),
+			"key-with-dash": i,
+		})
+	}
+
+	results, err := RenderBatch("./test/template.docx", datasets, 3)
+	if err != nil {
+		t.Error("batch rendering failed", err)
+		return
+	}
+	if len(results) != len(datasets) {
+		t.Errorf("unexpected amount of results, want=%d, have=%d", len(datasets), len(results))
+		return
+	}
+
+	for i, result := range results {
+		doc, err := OpenBytes(result)
+		if err != nil {
+			t.Errorf("result %d is not a valid docx: %s", i, err)
+			continue
+		}
+		text := writtenText(t, doc, DocumentXml)
+		if !strings.Contains(text, fmt.Sprintf("value-%d-value-%d", i, i)) {
+			t.Errorf("result %d does not contain the values of its dataset", i)
+		}
+		if strings.Contains(text, "{key}") {
+			t.Errorf("result %d still contains placeholders", i)
+		}
+	}
+}
+
+func TestRenderMany(t *testing.T) {
+	base := PlaceholderMap{"key": "base", "key-with-dash": "shared"}
+	overrides := []PlaceholderMap{{}, {"key": "first"}, {"key": "second", "key-with-dash": 2}}
+
+	results, err := RenderMany("./test/template.docx", base, overrides)
+	if err != nil {
+		t.Fatal("rendering failed", err)
+	}
+	expected := []string{"base-shared", "first-shared", "second-2"}
+	if len(results) != len(expected) {
+		t.Fatalf("unexpected amount of results, want=%d, have=%d", len(expected), len(results))
+	}
+	for i, result := range results {
+		doc, err := OpenBytes(result)
+		if err != nil {
+			t.Fatalf("result %d is not a valid docx: %s", i, err)
+		}
+		if text := writtenText(t, doc, DocumentXml); !strings.Contains(text, expected[i]) {
+			t.Errorf("result %d does not contain %s", i, expected[i])
+		}
+	}
+	if base["key"] != "base" || len(base) != 2 {
+		t.Errorf("base was modified, have=%v", base)
+	}
+}
+
+func TestDocument_Clone(t *testing.T) {
+	doc, err := Open("./test/template.docx")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer doc.Close()
+
+	clone := doc.Clone()
+	if err := clone.ReplaceAll(PlaceholderMap{"key": "cloned"}); err != nil {
+		t.Error("replacing in clone failed", err)
+		return
+	}
+
+	if !strings.Contains(writtenText(t, clone, DocumentXml), "cloned") {
+		t.Error("clone was not modified")
+	}
+	if strings.Contains(writtenText(t, doc, DocumentXml), "cloned") {
+		t.Error("modifying the clone must not modify the original document")
+	}
+
+	// the original must still be usable after the clone has been modified
+	if err := doc.ReplaceAll(PlaceholderMap{"key": "original"}); err != nil {
+		t.Error("replacing in original failed", err)
+	}
+}
@@ -0,0 +1,131 @@
+package docx
+
+// Clone returns a deep copy of the document which can be modified independently.
+// Cloning is much cheaper than opening and parsing the same docx again since all parsed state is copied.
+// This allows to parse a template once and render it many times, even concurrently.
+//
+// The clone shares the underlying zip archive with the original (read-only), but does not own
+// the file handle. Thus only the original document must be closed, and not before all clones are written.
+func (d *Document) Clone() *Document {
+	clone := &Document{}
+	d.cloneInto(clone)
+	return clone
+}
+
+// cloneInto overwrites the state of the clone with a deep copy of the document.
+// The file buffers of the clone are reused if possible, the clone must not be used concurrently.
+func (d *Document) cloneInto(clone *Document) {
+	files, rawFiles := clone.files, clone.rawFiles
+	*clone = Document{
+		path:             d.path,
+		zipFile:          d.zipFile,
+		files:            make(FileMap),
+		headerFiles:      append([]string(nil), d.headerFiles...),
+		footerFiles:      append([]string(nil), d.footerFiles...),
+		runParsers:       make(map[string]*RunParser),
+		filePlaceholders: make(map[string][]*Placeholder),
+		fileReplacers:    make(map[string]*Replacer),
+		rawFiles:         make(FileMap),
+
+		normalizeWhitespace: d.normalizeWhitespace,
+		matcher:             d.matcher,
+		removeEmptyRuns:     d.removeEmptyRuns,
+		preWriteHook:        d.preWriteHook,
+		preprocessor:        d.preprocessor,
+
+		stripLastRenderedPageBreaks: d.stripLastRenderedPageBreaks,
+		markFieldsDirty:             d.markFieldsDirty,
+		checkStrayDelimiters:        d.checkStrayDelimiters,
+		uncompressed:                d.uncompressed,
+		preserveUnchangedFiles:      d.preserveUnchangedFiles,
+		newlineMode:                 d.newlineMode,
+		lenientCount:                d.lenientCount,
+		recursiveValues:             d.recursiveValues,
+		maxValueLength:              d.maxValueLength,
+		missingPlaceholderValue:     d.missingPlaceholderValue,
+		delimiters:                  d.delimiters,
+		escapeDelimiters:            d.escapeDelimiters,
+	}
+
+	for name, data := range d.files {
+		clone.files[name] = append(files[name][:0], data...)
+	}
+	for name, data := range d.rawFiles {
+		clone.rawFiles[name] = append(rawFiles[name][:0], data...)
+	}
+	for key, transformers := range d.transformers {
+		if clone.transformers == nil {
+			clone.transformers = make(map[string][]Transformer)
+		}
+		clone.transformers[key] = append([]Transformer(nil), transformers...)
+	}
+	for name, mismatch := range d.countMismatches {
+		if clone.countMismatches == nil {
+			clone.countMismatches = make(map[string]CountMismatch)
+		}
+		clone.countMismatches[name] = mismatch
+	}
+
+	for name, parser := range d.runParsers {
+		// runs are referenced by the parser, the placeholder fragments and the replacer.
+		// The mapping ensures that all of them reference the same copied run afterwards.
+		runs := make(map[*Run]*Run)
+		cloneRun := func(run *Run) *Run {
+			if cloned, exists := runs[run]; exists {
+				return cloned
+			}
+			cloned := *run
+			runs[run] = &cloned
+			return &cloned
+		}
+
+		clonedParser := &RunParser{
+			doc:       copyBytes(parser.doc),
+			config:    parser.config,
+			regexes:   parser.regexes,
+			lastRunID: parser.lastRunID,
+		}
+		for _, run := range parser.runs {
+			clonedParser.runs = append(clonedParser.runs, cloneRun(run))
+		}
+		clone.runParsers[name] = clonedParser
+
+		var placeholders []*Placeholder
+		for _, placeholder := range d.filePlaceholders[name] {
+			clonedPlaceholder := &Placeholder{replaced: placeholder.replaced}
+			for _, fragment := range placeholder.Fragments {
+				clonedFragment := *fragment
+				clonedFragment.Run = cloneRun(fragment.Run)
+				clonedPlaceholder.Fragments = append(clonedPlaceholder.Fragments, &clonedFragment)
+			}
+			placeholders = append(placeholders, clonedPlaceholder)
+		}
+		clone.filePlaceholders[name] = placeholders
+
+		if replacer, exists := d.fileReplacers[name]; exists {
+			clonedReplacer := &Replacer{
+				document:     copyBytes(replacer.document),
+				placeholders: placeholders,
+				ReplaceCount: replacer.ReplaceCount,
+				BytesChanged: replacer.BytesChanged,
+
+				NormalizeWhitespace: replacer.NormalizeWhitespace,
+				Matcher:             replacer.Matcher,
+				RemoveEmptyRuns:     replacer.RemoveEmptyRuns,
+				delimiters:          replacer.delimiters,
+			}
+			for _, run := range replacer.distinctRuns {
+				clonedReplacer.distinctRuns = append(clonedReplacer.distinctRuns, cloneRun(run))
+			}
+			clone.fileReplacers[name] = clonedReplacer
+		}
+	}
+}
+
+// copyBytes returns a copy of the given byte slice which does not share the underlying array.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,323 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+)
+
+var (
+	// ErrContentControlNotFound is returned if there is no content control with the requested tag inside the document.
+	ErrContentControlNotFound = errors.New("content control not found in document")
+
+	// showingPlaceholderRegex matches the flag which tells Word that a content control displays its placeholder text
+	showingPlaceholderRegex = regexp.MustCompile(`<w:showingPlcHdr\s*/>`)
+	// placeholderTextStyleRegex matches the style reference Word uses to format the placeholder text of content controls
+	placeholderTextStyleRegex = regexp.MustCompile(`<w:rStyle w:val="PlaceholderText"\s*/>`)
+	// paragraphSingletonTagRegex matches an empty paragraph (<w:p/>), including eventually set attributes
+	paragraphSingletonTagRegex = regexp.MustCompile(`<w:p(\s[^>]*)?/>`)
+)
+
+// blockLevelParents are the elements in which a content control contains paragraphs instead of runs.
+var blockLevelParents = map[string]bool{
+	"body":        true,
+	"hdr":         true,
+	"ftr":         true,
+	"tc":          true,
+	"txbxContent": true,
+	"footnote":    true,
+	"endnote":     true,
+	"comment":     true,
+	"docPartBody": true,
+}
+
+// ContentControl is a content control (structured document tag) of the document.
+type ContentControl struct {
+	// Tag is the value of <w:tag> which identifies the content control.
+	Tag string
+	// Alias is the value of <w:alias>, the friendly name which Word displays.
+	Alias string
+	// Text is the current text of the content control.
+	Text string
+}
+
+// contentControl describes a parsed content control (<w:sdt>) and the positions of its parts inside a file.
+type contentControl struct {
+	ContentControl
+	ShowingPlaceholder bool
+
+	sdt        TagPair // <w:sdt> and </w:sdt>
+	properties TagPair // <w:sdtPr> and </w:sdtPr>
+	content    TagPair // <w:sdtContent> and </w:sdtContent>, equal if the content is a singleton tag
+	block      bool    // block-level content controls contain paragraphs instead of runs
+	date       bool    // date picker content controls (<w:date>) only accept dates
+}
+
+// ContentControls returns all content controls of the document, headers and footers in document order.
+// Nested content controls are returned as well, the text of the outer one includes the text of the nested ones.
+// Files which cannot be parsed are skipped.
+func (d *Document) ContentControls() []ContentControl {
+	var controls []ContentControl
+	for _, name := range d.parsedFiles() {
+		data := d.GetFile(name)
+		if data == nil {
+			continue
+		}
+		parsed, err := parseContentControls(data)
+		if err != nil {
+			logger.Printf("unable to parse content controls of %s: %s", name, err)
+			continue
+		}
+		for _, control := range parsed {
+			if d.escapeDelimiters {
+				control.Text = d.delimiters.unescape(control.Text)
+			}
+			controls = append(controls, control.ContentControl)
+		}
+	}
+	return controls
+}
+
+// ReplaceContentControl sets the text of all content controls (structured document tags) whose tag matches
+// the given tag. The value is put into the first text-run of the content control, all other text-runs are emptied.
+// If the content control currently shows its placeholder text, it is switched to display the value as regular text.
+//
+// Content controls are searched in all files (document, headers and footers), which are parsed again afterwards.
+// If no content control has the given tag, ErrContentControlNotFound is returned.
+func (d *Document) ReplaceContentControl(tag, value string) error {
+	found := false
+	for name, data := range d.files {
+		modified := false
+
+		// Content controls are replaced from the end of the file towards the start.
+		// That way, replacing one content control never shifts the start of the ones which are still to be replaced.
+		limit := int64(len(data)) + 1
+		for {
+			controls, err := parseContentControls(data)
+			if err != nil {
+				return fmt.Errorf("unable to parse content controls of %s: %w", name, err)
+			}
+
+			var control *contentControl
+			for i := range controls {
+				if controls[i].Tag == tag && controls[i].sdt.OpenTag.Start < limit {
+					control = &controls[i]
+				}
+			}
+			if control == nil {
+				break
+			}
+
+			data = setContentControlText(data, *control, d.escapeValue(value))
+			limit = control.sdt.OpenTag.Start
+			modified = true
+		}
+
+		if !modified {
+			continue
+		}
+		found = true
+		d.files[name] = data
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", ErrContentControlNotFound, tag)
+	}
+	return nil
+}
+
+// setContentControlText replaces the text of the given content control with the value and returns the modified data.
+func setContentControlText(data []byte, control contentControl, value string) []byte {
+	escaped := html.EscapeString(value)
+
+	var content []byte
+	if control.content.OpenTag != control.content.CloseTag {
+		content = copyBytes(data[control.content.OpenTag.End:control.content.CloseTag.Start])
+	}
+
+	textRuns := TextRunRegex.FindAllSubmatchIndex(content, -1)
+	if len(textRuns) > 0 {
+		// the value goes into the first text-run, all others are emptied
+		var modified []byte
+		last := 0
+		for i, textRun := range textRuns {
+			modified = append(modified, content[last:textRun[2]]...)
+			if i == 0 {
+				modified = append(modified, []byte(`<w:t xml:space="preserve">`+escaped)...)
+			} else {
+				modified = append(modified, content[textRun[2]:textRun[3]]...)
+			}
+			modified = append(modified, content[textRun[6]:textRun[7]]...)
+			last = textRun[1]
+		}
+		content = append(modified, content[last:]...)
+	} else {
+		run := `<w:r><w:t xml:space="preserve">` + escaped + `</w:t></w:r>`
+		content = insertRun(content, run, control.block)
+	}
+
+	if control.ShowingPlaceholder {
+		content = placeholderTextStyleRegex.ReplaceAll(content, nil)
+	}
+
+	// a missing or empty (<w:sdtContent/>) content is replaced as a whole
+	contentStart, contentEnd := control.content.OpenTag.Start, control.content.CloseTag.End
+	if control.content.OpenTag.End == 0 {
+		contentStart, contentEnd = control.sdt.CloseTag.Start, control.sdt.CloseTag.Start
+	}
+
+	var modified []byte
+	modified = append(modified, data[:contentStart]...)
+	modified = append(modified, []byte("<w:sdtContent>")...)
+	modified = append(modified, content...)
+	modified = append(modified, []byte("</w:sdtContent>")...)
+	modified = append(modified, data[contentEnd:]...)
+
+	// the properties precede the content, thus their positions are still valid
+	if control.ShowingPlaceholder {
+		properties := showingPlaceholderRegex.ReplaceAll(data[control.properties.OpenTag.Start:control.properties.CloseTag.End], nil)
+		modified = append(modified[:control.properties.OpenTag.Start], append(properties, modified[control.properties.CloseTag.End:]...)...)
+	}
+	return modified
+}
+
+// insertRun adds the run to a content without any text-runs.
+// Block-level content needs the run to be wrapped inside a paragraph.
+func insertRun(content []byte, run string, block bool) []byte {
+	if !block {
+		return append(content, []byte(run)...)
+	}
+	if paragraphEnd := bytes.Index(content, []byte("</w:p>")); paragraphEnd >= 0 {
+		return append(content[:paragraphEnd], append([]byte(run), content[paragraphEnd:]...)...)
+	}
+	if loc := paragraphSingletonTagRegex.FindSubmatchIndex(content); loc != nil {
+		var attrs []byte
+		if loc[2] >= 0 {
+			attrs = content[loc[2]:loc[3]]
+		}
+		paragraph := []byte("<w:p" + string(attrs) + ">" + run + "</w:p>")
+		return append(content[:loc[0]], append(paragraph, content[loc[1]:]...)...)
+	}
+	return append(content, []byte("<w:p>"+run+"</w:p>")...)
+}
+
+// contentControlText returns the text of all text-runs inside the content as it is displayed.
+func contentControlText(content []byte) string {
+	var text string
+	for _, match := range TextRunRegex.FindAllSubmatch(content, -1) {
+		text += html.UnescapeString(string(match[2]))
+	}
+	return text
+}
+
+// parseContentControls returns all content controls of the given data in document order.
+// Nested content controls are returned as well.
+func parseContentControls(data []byte) ([]contentControl, error) {
+	// use a custom reader which saves the current byte position
+	docReader := NewReader(string(data))
+	decoder := xml.NewDecoder(docReader)
+
+	var controls []contentControl
+	var openControls []int // indices of the content controls which are not closed yet
+	var elements []string  // local names of all currently open elements
+
+	// tagPosition returns the position of the tag which was read last
+	tagPosition := func() Position {
+		tagEndPos := docReader.Pos()
+		return Position{
+			Start: openBracketPos(data, tagEndPos-1),
+			End:   tagEndPos,
+		}
+	}
+	// parent returns the local name of the n-th parent element of the current element
+	parent := func(n int) string {
+		if len(elements) <= n {
+			return ""
+		}
+		return elements[len(elements)-1-n]
+	}
+	attr := func(elem xml.StartElement, local string) string {
+		for _, a := range elem.Attr {
+			if a.Name.Local == local {
+				return a.Value
+			}
+		}
+		return ""
+	}
+
+	for {
+		tok, err := decoder.Token()
+		if tok == nil || err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting token: %s", err)
+		}
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			var current *contentControl
+			if len(openControls) > 0 {
+				current = &controls[openControls[len(openControls)-1]]
+			}
+
+			switch {
+			case elem.Name.Local == "sdt":
+				controls = append(controls, contentControl{
+					sdt:   TagPair{OpenTag: tagPosition()},
+					block: blockLevelParents[parent(0)],
+				})
+				openControls = append(openControls, len(controls)-1)
+			case current != nil && parent(0) == "sdt" && elem.Name.Local == "sdtPr":
+				current.properties.OpenTag = tagPosition()
+			case current != nil && parent(0) == "sdt" && elem.Name.Local == "sdtContent":
+				current.content.OpenTag = tagPosition()
+			case current != nil && parent(0) == "sdtPr" && parent(1) == "sdt":
+				switch elem.Name.Local {
+				case "tag":
+					current.Tag = attr(elem, "val")
+				case "alias":
+					current.Alias = attr(elem, "val")
+				case "showingPlcHdr":
+					current.ShowingPlaceholder = true
+				case "date":
+					current.date = true
+				}
+			}
+			elements = append(elements, elem.Name.Local)
+
+		case xml.EndElement:
+			// parent() refers to the parent of the closed element from now on
+			elements = elements[:len(elements)-1]
+			if len(openControls) == 0 {
+				continue
+			}
+			current := &controls[openControls[len(openControls)-1]]
+
+			// the position of a singleton tag equals the position of its open tag
+			position := tagPosition()
+
+			switch {
+			case elem.Name.Local == "sdt":
+				current.sdt.CloseTag = position
+				openControls = openControls[:len(openControls)-1]
+			case elem.Name.Local == "sdtPr" && parent(0) == "sdt":
+				current.properties.CloseTag = position
+			case elem.Name.Local == "sdtContent" && parent(0) == "sdt":
+				current.content.CloseTag = position
+				if position != current.content.OpenTag {
+					current.Text = contentControlText(data[current.content.OpenTag.End:position.Start])
+				}
+			}
+		}
+	}
+
+	return controls, nil
+}
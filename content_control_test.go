@@ -0,0 +1,74 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceContentControl(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/content_controls.xml"))}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	values := map[string]string{
+		"customer_name": "Jane & Co {x}",
+		"notes":         "Updated notes",
+		"date":          "2024-01-01",
+	}
+	for tag, value := range values {
+		if err := doc.ReplaceContentControl(tag, value); err != nil {
+			t.Errorf("replacing content control %s failed: %s", tag, err)
+			return
+		}
+	}
+
+	if err := doc.ReplaceContentControl("missing", "value"); !errors.Is(err, ErrContentControlNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrContentControlNotFound, err)
+	}
+
+	data := string(doc.GetFile(DocumentXml))
+	if strings.Contains(data, "showingPlcHdr") || strings.Contains(data, "PlaceholderText") {
+		t.Error("expected placeholder text state of the content control to be removed")
+	}
+
+	expected := "Customer: Jane & Co {x}Updated notesDate: 2024-01-01, again: Jane & Co {x}"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
+
+func TestDocument_ContentControls(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/content_controls.xml"))}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []ContentControl{
+		{Tag: "customer_name", Alias: "Customer Name", Text: "Click or tap here to enter text."},
+		{Tag: "notes", Alias: "Notes", Text: "Some notes"},
+		{Tag: "date"},
+		{Tag: "customer_name", Text: "{customer}"},
+	}
+	controls := doc.ContentControls()
+	if len(controls) != len(expected) {
+		t.Errorf("unexpected amount of content controls, want=%d, have=%d", len(expected), len(controls))
+		return
+	}
+	for i, control := range controls {
+		if control != expected[i] {
+			t.Errorf("unexpected content control %d, want=%+v, have=%+v", i, expected[i], control)
+		}
+	}
+
+	if err := doc.ReplaceContentControl("date", "2024-01-01"); err != nil {
+		t.Error(err)
+		return
+	}
+	if text := doc.ContentControls()[2].Text; text != "2024-01-01" {
+		t.Errorf("unexpected text after replacing, want=%s, have=%s", "2024-01-01", text)
+	}
+}
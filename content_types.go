@@ -0,0 +1,159 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ContentTypesXml is the file which declares the content types of all parts inside the archive.
+	ContentTypesXml = "[Content_Types].xml"
+)
+
+// contentTypesXml is the root element of the ContentTypesXml.
+type contentTypesXml struct {
+	Defaults []struct {
+		Extension   string `xml:"Extension,attr"`
+		ContentType string `xml:"ContentType,attr"`
+	} `xml:"Default"`
+	Overrides []struct {
+		PartName    string `xml:"PartName,attr"`
+		ContentType string `xml:"ContentType,attr"`
+	} `xml:"Override"`
+}
+
+// contentTypes parses the ContentTypesXml of the document.
+func (d *Document) contentTypes() (contentTypesXml, []byte, error) {
+	var types contentTypesXml
+	data, err := d.readRawFile(ContentTypesXml)
+	if err != nil {
+		return types, nil, err
+	}
+	if err := xml.Unmarshal(data, &types); err != nil {
+		return types, nil, fmt.Errorf("unable to parse %s: %s", ContentTypesXml, err)
+	}
+	return types, data, nil
+}
+
+// contentType returns the content type of the given part. Overrides for the part take precedence over the
+// defaults for its extension. An empty string is returned if no content type is declared.
+func (d *Document) contentType(part string) (string, error) {
+	types, _, err := d.contentTypes()
+	if err != nil {
+		return "", err
+	}
+	for _, override := range types.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == part {
+			return override.ContentType, nil
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(part), ".")
+	for _, def := range types.Defaults {
+		if strings.EqualFold(def.Extension, ext) {
+			return def.ContentType, nil
+		}
+	}
+	return "", nil
+}
+
+// EnsureContentType declares the content type for all parts with the given extension (e.g. 'png' or '.png').
+// If the extension is already declared, its content type is updated if necessary.
+// The updated ContentTypesXml is written on Write().
+func (d *Document) EnsureContentType(ext, contentType string) error {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	types, data, err := d.contentTypes()
+	if err != nil {
+		return err
+	}
+	for _, def := range types.Defaults {
+		if strings.EqualFold(def.Extension, ext) && def.ContentType == contentType {
+			return nil
+		}
+	}
+
+	def := fmt.Sprintf(`<Default Extension="%s" ContentType="%s"/>`, html.EscapeString(ext), html.EscapeString(contentType))
+	defaultRegex := regexp.MustCompile(`(?i)<Default\s[^>]*Extension="` + regexp.QuoteMeta(html.EscapeString(ext)) + `"[^>]*>`)
+
+	if defaultRegex.Match(data) {
+		data = defaultRegex.ReplaceAllLiteral(data, []byte(def))
+	} else {
+		data = insertBeforeClosingTag(data, "</Types>", def)
+	}
+	d.setRawFile(ContentTypesXml, data)
+	return nil
+}
+
+// registerContentType ensures that the given part has the content type.
+// If the extension of the part is not declared yet, it is declared for the content type.
+// Otherwise, an override for the single part is added in case the declared content type differs.
+func (d *Document) registerContentType(part, contentType string) error {
+	current, err := d.contentType(part)
+	if err != nil {
+		return err
+	}
+	if current == contentType {
+		return nil
+	}
+
+	types, _, err := d.contentTypes()
+	if err != nil {
+		return err
+	}
+	ext := strings.TrimPrefix(path.Ext(part), ".")
+	declared := false
+	for _, def := range types.Defaults {
+		declared = declared || strings.EqualFold(def.Extension, ext)
+	}
+	if ext != "" && !declared {
+		return d.EnsureContentType(ext, contentType)
+	}
+	return d.setContentTypeOverride(part, contentType)
+}
+
+// addFile adds or replaces a part of the archive and registers its content type.
+// Features which add parts (e.g. media files) must use this to keep the ContentTypesXml valid.
+func (d *Document) addFile(part string, data []byte, contentType string) error {
+	if err := d.registerContentType(part, contentType); err != nil {
+		return err
+	}
+	d.setRawFile(part, data)
+	return nil
+}
+
+// setContentTypeOverride declares the content type of a single part, replacing an existing override of the part.
+func (d *Document) setContentTypeOverride(part, contentType string) error {
+	_, data, err := d.contentTypes()
+	if err != nil {
+		return err
+	}
+
+	partName := "/" + strings.TrimPrefix(part, "/")
+	override := fmt.Sprintf(`<Override PartName="%s" ContentType="%s"/>`, html.EscapeString(partName), html.EscapeString(contentType))
+	overrideRegex := regexp.MustCompile(`<Override\s[^>]*PartName="` + regexp.QuoteMeta(html.EscapeString(partName)) + `"[^>]*>`)
+
+	if overrideRegex.Match(data) {
+		data = overrideRegex.ReplaceAllLiteral(data, []byte(override))
+	} else {
+		data = insertBeforeClosingTag(data, "</Types>", override)
+	}
+	d.setRawFile(ContentTypesXml, data)
+	return nil
+}
+
+// insertBeforeClosingTag inserts the content right before the last occurrence of the closing tag.
+// If the closing tag does not exist, the content is appended.
+func insertBeforeClosingTag(data []byte, closingTag, content string) []byte {
+	pos := strings.LastIndex(string(data), closingTag)
+	if pos < 0 {
+		return append(data, []byte(content)...)
+	}
+	var modified []byte
+	modified = append(modified, data[:pos]...)
+	modified = append(modified, []byte(content)...)
+	modified = append(modified, data[pos:]...)
+	return modified
+}
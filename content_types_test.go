@@ -0,0 +1,55 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_EnsureContentType(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := doc.EnsureContentType(".svg", "image/svg+xml"); err != nil {
+			t.Error("ensuring content type failed", err)
+			return
+		}
+	}
+	if err := doc.addFile("word/media/image1.webp", []byte("RIFF"), "image/webp"); err != nil {
+		t.Error("adding file failed", err)
+		return
+	}
+
+	contentTypes := string(writtenFile(t, doc, ContentTypesXml))
+	if n := strings.Count(contentTypes, `<Default Extension="svg" ContentType="image/svg+xml"/>`); n != 1 {
+		t.Errorf("unexpected number of svg declarations, want=%d, have=%d", 1, n)
+	}
+	if !strings.Contains(contentTypes, `<Default Extension="webp" ContentType="image/webp"/>`) {
+		t.Error("expected content type of the added file to be declared")
+	}
+	if data := writtenFile(t, doc, "word/media/image1.webp"); string(data) != "RIFF" {
+		t.Errorf("added file was not written, have=%q", data)
+	}
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	n, err := doc.WriteTo(buf)
+	if err != nil {
+		t.Error("WriteTo failed", err)
+		return
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("unexpected amount of bytes written, want=%d, have=%d", buf.Len(), n)
+	}
+}
@@ -0,0 +1,63 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// CorePropertiesXml is the relative path where the core properties (author, timestamps, ...) reside inside the docx-archive.
+	CorePropertiesXml = "docProps/core.xml"
+	// W3CDTFLayout is the time layout of the W3CDTF format which is used for the timestamps inside the core properties.
+	W3CDTFLayout = "2006-01-02T15:04:05Z"
+)
+
+var (
+	// corePropertiesCloseTagRegex matches the close tag of the core properties root element.
+	corePropertiesCloseTagRegex = regexp.MustCompile(`</cp:coreProperties>`)
+	// corePropertiesOpenTagRegex matches the open tag of the core properties root element, including all attributes.
+	corePropertiesOpenTagRegex = regexp.MustCompile(`<cp:coreProperties[^>]*>`)
+)
+
+// TouchModified sets the 'dcterms:modified' timestamp of the core properties to the current time.
+// Without calling it, the generated document still carries the timestamp of the template.
+func (d *Document) TouchModified() error {
+	return d.setCoreDate("dcterms:modified", time.Now())
+}
+
+// TouchCreated sets the 'dcterms:created' timestamp of the core properties to the current time.
+func (d *Document) TouchCreated() error {
+	return d.setCoreDate("dcterms:created", time.Now())
+}
+
+// setCoreDate sets the given W3CDTF date element inside the core properties to the given time.
+// If the element does not exist yet, it is appended to the core properties.
+func (d *Document) setCoreDate(element string, t time.Time) error {
+	data, err := d.readRawFile(CorePropertiesXml)
+	if err != nil {
+		return err
+	}
+
+	elementRegex := regexp.MustCompile(fmt.Sprintf(`<%s\b[^>]*?(/>|>[^<]*</%s>)`, element, element))
+	value := fmt.Sprintf(`<%s xsi:type="dcterms:W3CDTF">%s</%s>`, element, t.UTC().Format(W3CDTFLayout), element)
+
+	switch {
+	case elementRegex.Match(data):
+		data = elementRegex.ReplaceAllLiteral(data, []byte(value))
+	case corePropertiesCloseTagRegex.Match(data):
+		data = corePropertiesCloseTagRegex.ReplaceAllLiteral(data, []byte(value+"</cp:coreProperties>"))
+	default:
+		return fmt.Errorf("%s does not contain the core properties element", CorePropertiesXml)
+	}
+
+	// the xsi:type attribute requires the xsi namespace, which might not be declared in sparse core properties
+	openTag := corePropertiesOpenTagRegex.Find(data)
+	if openTag != nil && !regexp.MustCompile(`\bxmlns:xsi=`).Match(openTag) {
+		withNamespace := string(openTag[:len(openTag)-1]) + ` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`
+		data = corePropertiesOpenTagRegex.ReplaceAllLiteral(data, []byte(withNamespace))
+	}
+
+	d.setRawFile(CorePropertiesXml, data)
+	return nil
+}
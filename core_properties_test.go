@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDocument_TouchModified(t *testing.T) {
+	doc, err := Open("./test/template.docx")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer doc.Close()
+
+	before := time.Now().UTC().Truncate(time.Second)
+	err = doc.TouchModified()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	err = doc.Write(buf)
+	if err != nil {
+		t.Error("unable to write", err)
+		return
+	}
+
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Error("failed to open written docx", err)
+		return
+	}
+	coreXml, err := written.readRawFile(CorePropertiesXml)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	match := regexp.MustCompile(`<dcterms:modified xsi:type="dcterms:W3CDTF">([^<]+)</dcterms:modified>`).FindSubmatch(coreXml)
+	if match == nil {
+		t.Errorf("dcterms:modified is missing in %s", coreXml)
+		return
+	}
+	modified, err := time.Parse(W3CDTFLayout, string(match[1]))
+	if err != nil {
+		t.Errorf("dcterms:modified is not in W3CDTF format: %s", err)
+		return
+	}
+	if modified.Before(before) {
+		t.Errorf("dcterms:modified was not updated, have=%s", modified)
+	}
+	if bytes.Count(coreXml, []byte("<dcterms:modified")) != 1 {
+		t.Errorf("dcterms:modified must only occur once")
+	}
+}
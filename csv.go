@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// FromCSVRow maps the values of a CSV row to the placeholder keys given by the headers.
+// Missing columns result in an empty value, columns without a header are ignored.
+func FromCSVRow(headers []string, row []string) PlaceholderMap {
+	placeholderMap := make(PlaceholderMap)
+	for i, header := range headers {
+		key := strings.TrimSpace(header)
+		if key == "" {
+			continue
+		}
+		value := ""
+		if i < len(row) {
+			value = row[i]
+		}
+		placeholderMap[key] = value
+	}
+	return placeholderMap
+}
+
+// RenderCSV renders the template once for every data row of the CSV file, like a mail merge.
+// The first row of the CSV file is the header which holds the placeholder keys, see FromCSVRow.
+// Rows may have a different amount of columns than the header. The rendering itself is done by RenderBatch,
+// thus the results have the same order as the rows and a *BatchError is returned if rows could not be rendered.
+func RenderCSV(templatePath, csvPath string) ([][]byte, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open csv file: %s", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read csv file: %s", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv file %s has no header", csvPath)
+	}
+
+	// the byte order mark Excel writes would become part of the first key
+	headers := records[0]
+	headers[0] = strings.TrimPrefix(headers[0], "\ufeff")
+
+	var datasets []PlaceholderMap
+	for _, row := range records[1:] {
+		datasets = append(datasets, FromCSVRow(headers, row))
+	}
+	return RenderBatch(templatePath, datasets, runtime.NumCPU())
+}
@@ -0,0 +1,71 @@
+package docx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromCSVRow(t *testing.T) {
+	headers := []string{"name", " city ", "", "country"}
+
+	tests := []struct {
+		name     string
+		row      []string
+		expected PlaceholderMap
+	}{
+		{
+			name:     "complete row",
+			row:      []string{"Alice", "Berlin", "ignored", "Germany"},
+			expected: PlaceholderMap{"name": "Alice", "city": "Berlin", "country": "Germany"},
+		},
+		{
+			name:     "missing columns",
+			row:      []string{"Bob"},
+			expected: PlaceholderMap{"name": "Bob", "city": "", "country": ""},
+		},
+		{
+			name:     "additional columns",
+			row:      []string{"Carol", "Paris", "", "France", "extra"},
+			expected: PlaceholderMap{"name": "Carol", "city": "Paris", "country": "France"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if placeholderMap := FromCSVRow(headers, tt.row); !reflect.DeepEqual(placeholderMap, tt.expected) {
+				t.Errorf("unexpected placeholder map, want=%v, have=%v", tt.expected, placeholderMap)
+			}
+		})
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "data.csv")
+	data := "\ufeffkey,key-with-dash\n" +
+		"first,1\n" +
+		"\"quoted, with comma\",\"2\"\n" +
+		"missing\n"
+	if err := os.WriteFile(csvPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := RenderCSV("./test/template.docx", csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"first-first", "quoted, with comma-quoted, with comma", "missing-missing"}
+	if len(results) != len(expected) {
+		t.Fatalf("unexpected number of documents, want=%d, have=%d", len(expected), len(results))
+	}
+	for i, result := range results {
+		doc, err := OpenBytes(result)
+		if err != nil {
+			t.Fatalf("result %d is not a valid docx: %s", i, err)
+		}
+		if text := writtenText(t, doc, DocumentXml); !strings.Contains(text, expected[i]) {
+			t.Errorf("result %d does not contain '%s'", i, expected[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// CustomPropertiesXml is the relative path where the custom document properties reside inside the docx-archive.
+	CustomPropertiesXml = "docProps/custom.xml"
+	// CustomPropertiesContentType is the content type of the custom properties part.
+	CustomPropertiesContentType = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+	// CustomPropertiesRelationshipType is the type of the package relationship to the custom properties part.
+	CustomPropertiesRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+
+	// customPropertyFormatID is the format id which Word uses for all user-defined custom properties.
+	customPropertyFormatID = "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}"
+	// customPropertiesXml is the content of a new custom properties part.
+	customPropertiesXml = XmlDeclaration + "\n" +
+		`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" ` +
+		`xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"></Properties>`
+)
+
+// customProperty is a single <property> of the custom properties part.
+type customProperty struct {
+	Name   string `xml:"name,attr"`
+	PID    int    `xml:"pid,attr"`
+	Values []struct {
+		Value string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// customPropertiesRoot is the root element of the custom properties part.
+type customPropertiesRoot struct {
+	Properties []customProperty `xml:"property"`
+}
+
+// CustomProperties returns the values of all custom document properties by their name.
+// Values are returned as they are stored, e.g. 'true' for booleans. If the document has no custom properties,
+// an empty map is returned.
+func (d *Document) CustomProperties() (map[string]string, error) {
+	properties, err := d.customProperties()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(properties))
+	for _, property := range properties {
+		if len(property.Values) > 0 {
+			values[property.Name] = property.Values[0].Value
+		}
+	}
+	return values, nil
+}
+
+// SetCustomProperty sets the custom document property with the given name, creating the custom properties part
+// if the document does not have one. Templates can display custom properties with DOCPROPERTY fields,
+// Word shows the new value once the fields are updated.
+//
+// Supported values are strings, integers, floats, booleans and time.Time. Other values are stored as
+// string using fmt.Sprint.
+func (d *Document) SetCustomProperty(name string, value interface{}) error {
+	var element, text string
+	switch v := value.(type) {
+	case string:
+		element, text = "vt:lpwstr", v
+	case bool:
+		element, text = "vt:bool", strconv.FormatBool(v)
+	case int8, int16, int32, uint8, uint16:
+		element, text = "vt:i4", fmt.Sprint(v)
+	case int, int64, uint32:
+		// int has 64 bits on most platforms, a vt:i4 would be truncated by Word
+		element, text = "vt:i8", fmt.Sprint(v)
+	case uint, uint64:
+		element, text = "vt:ui8", fmt.Sprint(v)
+	case float32:
+		element, text = "vt:r8", strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		element, text = "vt:r8", strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		element, text = "vt:filetime", v.UTC().Format(W3CDTFLayout)
+	default:
+		element, text = "vt:lpwstr", fmt.Sprint(v)
+	}
+	return d.setCustomProperty(name, fmt.Sprintf("<%s>%s</%s>", element, html.EscapeString(text), element))
+}
+
+// SetCustomPropertyString sets a custom document property of type text.
+func (d *Document) SetCustomPropertyString(name, value string) error {
+	return d.SetCustomProperty(name, value)
+}
+
+// SetCustomPropertyNumber sets a custom document property of type number.
+func (d *Document) SetCustomPropertyNumber(name string, value float64) error {
+	return d.SetCustomProperty(name, value)
+}
+
+// SetCustomPropertyBool sets a custom document property of type yes or no.
+func (d *Document) SetCustomPropertyBool(name string, value bool) error {
+	return d.SetCustomProperty(name, value)
+}
+
+// SetCustomPropertyDate sets a custom document property of type date.
+func (d *Document) SetCustomPropertyDate(name string, value time.Time) error {
+	return d.SetCustomProperty(name, value)
+}
+
+// setCustomProperty sets the value element of the property, keeping its format id and pid if it already exists.
+func (d *Document) setCustomProperty(name, valueElement string) error {
+	data, err := d.readRawFile(CustomPropertiesXml)
+	if err != nil {
+		if err := d.addFile(CustomPropertiesXml, []byte(customPropertiesXml), CustomPropertiesContentType); err != nil {
+			return err
+		}
+		if _, err := d.AddRelationship("", CustomPropertiesRelationshipType, CustomPropertiesXml, false); err != nil {
+			return err
+		}
+		data = []byte(customPropertiesXml)
+	}
+
+	properties, err := d.customProperties()
+	if err != nil {
+		return err
+	}
+
+	propertyRegex := regexp.MustCompile(`(?s)(<property\s[^>]*name="` + regexp.QuoteMeta(html.EscapeString(name)) + `"[^>]*>).*?</property>`)
+	if loc := propertyRegex.FindSubmatchIndex(data); loc != nil {
+		property := string(data[loc[2]:loc[3]]) + valueElement + "</property>"
+		data = append(data[:loc[0]:loc[0]], append([]byte(property), data[loc[1]:]...)...)
+	} else {
+		// pids of user-defined properties start at 2
+		pid := 1
+		for _, property := range properties {
+			if property.PID > pid {
+				pid = property.PID
+			}
+		}
+		property := fmt.Sprintf(`<property fmtid="%s" pid="%d" name="%s">%s</property>`,
+			customPropertyFormatID, pid+1, html.EscapeString(name), valueElement)
+		data = insertBeforeClosingTag(data, "</Properties>", property)
+	}
+
+	d.setRawFile(CustomPropertiesXml, data)
+	return nil
+}
+
+// customProperties returns all custom properties, or none if the document does not have a custom properties part.
+func (d *Document) customProperties() ([]customProperty, error) {
+	data, err := d.readRawFile(CustomPropertiesXml)
+	if err != nil {
+		return nil, nil
+	}
+	var root customPropertiesRoot
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", CustomPropertiesXml, err)
+	}
+	return root.Properties, nil
+}
@@ -0,0 +1,107 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_SetCustomProperty(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	if err := doc.SetCustomPropertyString("Client", "ACME & Sons"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCustomPropertyNumber("Amount", 12.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCustomPropertyBool("Approved", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCustomPropertyDate("Due", date); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCustomProperty("Client", "Initech"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties, err := written.CustomProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"Client": "Initech", "Amount": "12.5", "Approved": "true", "Due": "2024-03-01T12:30:00Z"}
+	for name, value := range expected {
+		if properties[name] != value {
+			t.Errorf("unexpected value of %s, want=%s, have=%s", name, value, properties[name])
+		}
+	}
+	if len(properties) != len(expected) {
+		t.Errorf("unexpected properties, want=%v, have=%v", expected, properties)
+	}
+
+	custom := string(writtenFile(t, written, CustomPropertiesXml))
+	for _, element := range []string{`pid="2" name="Client"><vt:lpwstr>Initech</vt:lpwstr>`, `pid="3" name="Amount"><vt:r8>`,
+		`pid="4" name="Approved"><vt:bool>`, `pid="5" name="Due"><vt:filetime>`} {
+		if !strings.Contains(custom, element) {
+			t.Errorf("missing %s, have=%s", element, custom)
+		}
+	}
+
+	contentType, err := written.contentType(CustomPropertiesXml)
+	if err != nil || contentType != CustomPropertiesContentType {
+		t.Errorf("unexpected content type, want=%s, have=%s (%v)", CustomPropertiesContentType, contentType, err)
+	}
+	rels, err := written.Relationships("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered := false
+	for _, rel := range rels {
+		registered = registered || (rel.Type == CustomPropertiesRelationshipType && rel.Target == CustomPropertiesXml)
+	}
+	if !registered {
+		t.Errorf("custom properties are not referenced by the package, have=%v", rels)
+	}
+}
+
+func TestDocument_SetCustomProperty_Integers(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: int32(-7), expected: `<vt:i4>-7</vt:i4>`},
+		{value: uint16(7), expected: `<vt:i4>7</vt:i4>`},
+		{value: 42, expected: `<vt:i8>42</vt:i8>`},
+		{value: int64(-1 << 40), expected: `<vt:i8>-1099511627776</vt:i8>`},
+		{value: uint32(1 << 31), expected: `<vt:i8>2147483648</vt:i8>`},
+		{value: uint64(1 << 63), expected: `<vt:ui8>9223372036854775808</vt:ui8>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			doc, err := OpenBytes(newTestDocx(t, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.SetCustomProperty("Count", tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if custom := string(writtenFile(t, doc, CustomPropertiesXml)); !strings.Contains(custom, tt.expected) {
+				t.Errorf("unexpected value element, want=%s, have=%s", tt.expected, custom)
+			}
+		})
+	}
+}
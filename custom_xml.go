@@ -0,0 +1,198 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// CustomXmlDir is the directory inside the docx-archive in which the custom XML data stores reside.
+	CustomXmlDir = "customXml/"
+)
+
+var (
+	// ErrElementNotFound is returned if an element could not be located inside an XML file.
+	ErrElementNotFound = errors.New("element not found")
+)
+
+// SetCustomXMLValue sets the text content of an element inside a custom XML part (e.g. 'customXml/item1.xml').
+// Content controls which are bound to the custom XML data store will display the new value.
+//
+// The part may be given with or without the 'customXml/' prefix.
+// The xpath is a simple path of element names, namespace prefixes are ignored:
+//   - '/root/customer/name' matches the element by its absolute path
+//   - '//name' or 'name' matches the first element with the given name, wherever it is
+//
+// All children of the matched element are replaced by the value.
+func (d *Document) SetCustomXMLValue(part, xpath, value string) error {
+	part = customXmlPartName(part)
+	data, err := d.readRawFile(part)
+	if err != nil {
+		return err
+	}
+
+	elem, err := findXmlElement(data, xpath)
+	if err != nil {
+		return fmt.Errorf("unable to find '%s' in %s: %w", xpath, part, err)
+	}
+
+	escaped := new(bytes.Buffer)
+	if err := xml.EscapeText(escaped, []byte(value)); err != nil {
+		return err
+	}
+
+	var modified []byte
+	if elem.OpenTag == elem.CloseTag {
+		// singleton tag (e.g. <name/>) needs to be expanded to be able to hold a value
+		tag := data[elem.OpenTag.Start:elem.OpenTag.End]
+		openTag := bytes.TrimRight(tag[:len(tag)-2], " ")
+		name := bytes.Fields(openTag[1:])[0]
+		modified = append(modified, data[:elem.OpenTag.Start]...)
+		modified = append(modified, openTag...)
+		modified = append(modified, '>')
+		modified = append(modified, escaped.Bytes()...)
+		modified = append(modified, []byte(fmt.Sprintf("</%s>", name))...)
+		modified = append(modified, data[elem.CloseTag.End:]...)
+	} else {
+		modified = append(modified, data[:elem.OpenTag.End]...)
+		modified = append(modified, escaped.Bytes()...)
+		modified = append(modified, data[elem.CloseTag.Start:]...)
+	}
+
+	d.setRawFile(part, modified)
+	return nil
+}
+
+// CustomXMLValue returns the text content of an element inside a custom XML part.
+// The part and xpath are handled just like in SetCustomXMLValue.
+func (d *Document) CustomXMLValue(part, xpath string) (string, error) {
+	part = customXmlPartName(part)
+	data, err := d.readRawFile(part)
+	if err != nil {
+		return "", err
+	}
+
+	elem, err := findXmlElement(data, xpath)
+	if err != nil {
+		return "", fmt.Errorf("unable to find '%s' in %s: %w", xpath, part, err)
+	}
+	if elem.OpenTag == elem.CloseTag {
+		return "", nil
+	}
+
+	var text string
+	decoder := xml.NewDecoder(bytes.NewReader(data[elem.OpenTag.End:elem.CloseTag.Start]))
+	for {
+		tok, err := decoder.Token()
+		if tok == nil || err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error getting token: %s", err)
+		}
+		if charData, ok := tok.(xml.CharData); ok {
+			text += string(charData)
+		}
+	}
+	return text, nil
+}
+
+// customXmlPartName ensures that the given part name is prefixed with the CustomXmlDir.
+func customXmlPartName(part string) string {
+	if strings.HasPrefix(part, CustomXmlDir) {
+		return part
+	}
+	return CustomXmlDir + part
+}
+
+// findXmlElement returns the positions of the open and close tag of the first element matching the xpath.
+// If the element is a singleton tag, OpenTag and CloseTag are equal.
+func findXmlElement(data []byte, xpath string) (TagPair, error) {
+	absolute := strings.HasPrefix(xpath, "/") && !strings.HasPrefix(xpath, "//")
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(xpath, "/"), "/") {
+		if i := strings.IndexRune(segment, ':'); i >= 0 {
+			segment = segment[i+1:]
+		}
+		segments = append(segments, segment)
+	}
+
+	// matches checks whether the current element stack matches the xpath segments
+	matches := func(stack []string) bool {
+		if absolute && len(stack) != len(segments) {
+			return false
+		}
+		if len(stack) < len(segments) {
+			return false
+		}
+		offset := len(stack) - len(segments)
+		for i, segment := range segments {
+			if stack[offset+i] != segment {
+				return false
+			}
+		}
+		return true
+	}
+
+	// use a custom reader which saves the current byte position
+	docReader := NewReader(string(data))
+	decoder := xml.NewDecoder(docReader)
+
+	var stack []string
+	var found TagPair
+	foundDepth := -1
+
+	for {
+		tok, err := decoder.Token()
+		if tok == nil || err == io.EOF {
+			break
+		}
+		if err != nil {
+			return TagPair{}, fmt.Errorf("error getting token: %s", err)
+		}
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, elem.Name.Local)
+			if foundDepth < 0 && matches(stack) {
+				tagEndPos := docReader.Pos()
+				found.OpenTag = Position{
+					Start: openBracketPos(data, tagEndPos-1),
+					End:   tagEndPos,
+				}
+				foundDepth = len(stack)
+			}
+
+		case xml.EndElement:
+			if foundDepth == len(stack) {
+				tagEndPos := docReader.Pos()
+				if tagEndPos == found.OpenTag.End && data[tagEndPos-2] == '/' {
+					found.CloseTag = found.OpenTag
+				} else {
+					found.CloseTag = Position{
+						Start: openBracketPos(data, tagEndPos-1),
+						End:   tagEndPos,
+					}
+				}
+				return found, nil
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return TagPair{}, ErrElementNotFound
+}
+
+// openBracketPos searches the matching '<' for a close bracket ('>') given it's position.
+func openBracketPos(data []byte, endBracketPos int64) int64 {
+	for i := endBracketPos; i >= 0; i-- {
+		if data[i] == '<' {
+			return i
+		}
+	}
+	return 0
+}
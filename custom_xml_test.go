@@ -0,0 +1,54 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+const customXmlItem = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ns0:invoice xmlns:ns0="urn:example:invoice"><ns0:customer><ns0:name>Placeholder Inc.</ns0:name><ns0:city/></ns0:customer><ns0:total>0.00</ns0:total></ns0:invoice>`
+
+func TestDocument_SetCustomXMLValue(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{"customXml/item1.xml": customXmlItem}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	values := map[string]string{
+		"/invoice/customer/name": "Smith & Sons",
+		"city":                   "Berlin",
+		"//ns0:total":            "42.00",
+	}
+	for xpath, value := range values {
+		if err := doc.SetCustomXMLValue("item1.xml", xpath, value); err != nil {
+			t.Errorf("SetCustomXMLValue(%s) failed: %s", xpath, err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		t.Error("unable to write", err)
+		return
+	}
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Error("failed to open written docx", err)
+		return
+	}
+
+	for xpath, expected := range values {
+		value, err := written.CustomXMLValue("customXml/item1.xml", xpath)
+		if err != nil {
+			t.Errorf("CustomXMLValue(%s) failed: %s", xpath, err)
+			continue
+		}
+		if value != expected {
+			t.Errorf("unexpected value for %s, want=%s, have=%s", xpath, expected, value)
+		}
+	}
+
+	if err := doc.SetCustomXMLValue("item1.xml", "/customer/name", "foo"); err == nil {
+		t.Error("expected an error for a relative path which is given as absolute path")
+	}
+}
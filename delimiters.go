@@ -0,0 +1,135 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// delimiters are the open and close delimiter of the placeholders of a document.
+type delimiters struct {
+	open  rune
+	close rune
+}
+
+// defaultDelimiters are used by all documents which do not set their own delimiters
+// as well as by the package level functions like ParsePlaceholders or EscapeDelimiters.
+var defaultDelimiters = delimiters{open: OpenDelimiter, close: CloseDelimiter}
+
+// newDelimiters returns the delimiters if they can be used to delimit placeholders.
+func newDelimiters(open, close rune) (delimiters, error) {
+	if !utf8.ValidRune(open) || !utf8.ValidRune(close) || open == utf8.RuneError || close == utf8.RuneError {
+		return delimiters{}, fmt.Errorf("invalid delimiters %q and %q", open, close)
+	}
+	if open == close {
+		return delimiters{}, fmt.Errorf("the open and close delimiter must differ, have %c", open)
+	}
+	return delimiters{open: open, close: close}, nil
+}
+
+// SetDelimiters changes the delimiters of the placeholders inside this document, e.g. to guillemets ('«' and '»').
+// Delimiters may be any rune, including multi-byte runes. Other documents are not affected, they keep using
+// OpenDelimiter and CloseDelimiter unless they set their own delimiters. All files are parsed again afterwards.
+func (d *Document) SetDelimiters(open, close rune) error {
+	delims, err := newDelimiters(open, close)
+	if err != nil {
+		return err
+	}
+	d.delimiters = delims
+	for name := range d.files {
+		d.files[name] = d.GetFile(name)
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delimiters returns the open and close delimiter of the placeholders inside this document.
+func (d *Document) Delimiters() (open, close rune) {
+	return d.delimiters.open, d.delimiters.close
+}
+
+// String returns the delimiters of an empty placeholder, e.g. '{}'.
+func (delims delimiters) String() string {
+	return string(delims.open) + string(delims.close)
+}
+
+// isDelimited returns true if the first and last rune of the string are the open and close delimiter.
+func (delims delimiters) isDelimited(s string) bool {
+	if len(s) < 1 {
+		return false
+	}
+	// delimiters may be multi-byte runes, thus the runes need to be decoded instead of comparing bytes
+	first, _ := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	return first == delims.open && last == delims.close
+}
+
+// add wraps the string with the delimiters, unless it is already a delimited placeholder.
+func (delims delimiters) add(s string) string {
+	if delims.isDelimited(s) {
+		return s
+	}
+	return fmt.Sprintf("%c%s%c", delims.open, s, delims.close)
+}
+
+// remove trims all delimiters from both ends of a delimited placeholder.
+func (delims delimiters) remove(s string) string {
+	if !delims.isDelimited(s) {
+		return s
+	}
+	return strings.Trim(s, delims.String())
+}
+
+// trim removes exactly one open and close delimiter from a delimited placeholder.
+// Unlike remove, delimiters which are part of the text (e.g. '\d{2}') are kept.
+func (delims delimiters) trim(s string) string {
+	if !delims.isDelimited(s) {
+		return s
+	}
+	_, first := utf8.DecodeRuneInString(s)
+	_, last := utf8.DecodeLastRuneInString(s)
+	if first+last > len(s) {
+		return ""
+	}
+	return s[first : len(s)-last]
+}
+
+// key adds the delimiters to the placeholder key, unless it already contains them.
+func (delims delimiters) key(placeholderKey string) string {
+	if !strings.ContainsRune(placeholderKey, delims.open) ||
+		!strings.ContainsRune(placeholderKey, delims.close) {
+		return delims.add(placeholderKey)
+	}
+	return placeholderKey
+}
+
+// normalize normalizes the whitespace of the placeholder, see NormalizePlaceholder.
+func (delims delimiters) normalize(s string) string {
+	delimited := delims.isDelimited(s)
+	if delimited {
+		s = delims.remove(s)
+	}
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+	if delimited {
+		return delims.add(s)
+	}
+	return s
+}
+
+// withDefaults replaces the delimiters of a delimited placeholder by OpenDelimiter and CloseDelimiter.
+// Matchers are independent of the delimiters of a document, they always receive the default ones.
+func (delims delimiters) withDefaults(s string) string {
+	if delims == defaultDelimiters || !delims.isDelimited(s) {
+		return s
+	}
+	return fmt.Sprintf("%c%s%c", OpenDelimiter, delims.trim(s), CloseDelimiter)
+}
@@ -0,0 +1,151 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrKeysMismatch is returned by RequireExactKeys if the keys of the PlaceholderMap do not match the template.
+var ErrKeysMismatch = errors.New("placeholder keys do not match the template")
+
+// KeysMismatchError lists the discrepancies between the keys of a PlaceholderMap and the placeholders of a template.
+type KeysMismatchError struct {
+	// Missing are the keys of the template which are not part of the map.
+	Missing []string
+	// Extra are the keys of the map which do not exist in the template.
+	Extra []string
+}
+
+// Error implements the error interface and lists all missing and extra keys.
+func (e *KeysMismatchError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		problems = append(problems, "extra "+strings.Join(e.Extra, ", "))
+	}
+	return fmt.Sprintf("%s: %s", ErrKeysMismatch, strings.Join(problems, "; "))
+}
+
+// Unwrap returns ErrKeysMismatch which allows to check for it using errors.Is().
+func (e *KeysMismatchError) Unwrap() error {
+	return ErrKeysMismatch
+}
+
+// PlaceholderDiffKind describes how a placeholder differs between two documents.
+type PlaceholderDiffKind string
+
+const (
+	// PlaceholderAdded is reported for keys which only exist in the second document.
+	PlaceholderAdded PlaceholderDiffKind = "added"
+	// PlaceholderRemoved is reported for keys which only exist in the first document.
+	PlaceholderRemoved PlaceholderDiffKind = "removed"
+	// PlaceholderChanged is reported for keys which exist in both documents, but not equally often.
+	PlaceholderChanged PlaceholderDiffKind = "changed"
+)
+
+// PlaceholderDiff is a single difference between the placeholders of two documents.
+type PlaceholderDiff struct {
+	Kind PlaceholderDiffKind
+	// Key is the placeholder key without delimiters.
+	Key string
+	// Before and After are the occurrences of the key in the first and second document.
+	Before, After int
+}
+
+// String returns a human-readable line for reports, e.g. '+ {name}' or '~ {date} (1 -> 2)'.
+func (d PlaceholderDiff) String() string {
+	placeholder := AddPlaceholderDelimiter(d.Key)
+	switch d.Kind {
+	case PlaceholderAdded:
+		return "+ " + placeholder
+	case PlaceholderRemoved:
+		return "- " + placeholder
+	default:
+		return fmt.Sprintf("~ %s (%d -> %d)", placeholder, d.Before, d.After)
+	}
+}
+
+// PlaceholderKeys returns the keys (without delimiters) of all placeholders of the document, headers and footers
+// which have not been replaced yet. Every key is returned once, sorted alphabetically.
+func (d *Document) PlaceholderKeys() []string {
+	var keys []string
+	for key := range d.placeholderKeyCounts() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RequireExactKeys returns a *KeysMismatchError if the keys of the PlaceholderMap differ from PlaceholderKeys, that is
+// if a placeholder of the template has no value or if the map contains keys which do not exist in the template.
+// It is meant to be called before replacing, since replaced placeholders are no longer part of PlaceholderKeys.
+// Keys may be given with or without delimiters.
+func (d *Document) RequireExactKeys(placeholderMap PlaceholderMap) error {
+	given := make(map[string]bool, len(placeholderMap))
+	for key := range placeholderMap {
+		given[d.delimiters.remove(key)] = true
+	}
+
+	mismatch := &KeysMismatchError{}
+	template := make(map[string]bool)
+	for _, key := range d.PlaceholderKeys() {
+		template[key] = true
+		if !given[key] {
+			mismatch.Missing = append(mismatch.Missing, key)
+		}
+	}
+	for key := range given {
+		if !template[key] {
+			mismatch.Extra = append(mismatch.Extra, key)
+		}
+	}
+	sort.Strings(mismatch.Extra)
+
+	if len(mismatch.Missing) > 0 || len(mismatch.Extra) > 0 {
+		return mismatch
+	}
+	return nil
+}
+
+// placeholderKeyCounts returns how often every key occurs in the document, headers and footers.
+func (d *Document) placeholderKeyCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, file := range d.parsedFiles() {
+		for _, placeholder := range d.filePlaceholders[file] {
+			if placeholder.replaced {
+				continue
+			}
+			counts[d.delimiters.remove(placeholder.Text(d.files[file]))]++
+		}
+	}
+	return counts
+}
+
+// DiffPlaceholders compares the placeholders of two documents, e.g. two versions of a template.
+// The differences are sorted by their key.
+func DiffPlaceholders(a, b *Document) []PlaceholderDiff {
+	before, after := a.placeholderKeyCounts(), b.placeholderKeyCounts()
+
+	var diffs []PlaceholderDiff
+	for key, count := range before {
+		switch {
+		case after[key] == 0:
+			diffs = append(diffs, PlaceholderDiff{Kind: PlaceholderRemoved, Key: key, Before: count})
+		case after[key] != count:
+			diffs = append(diffs, PlaceholderDiff{Kind: PlaceholderChanged, Key: key, Before: count, After: after[key]})
+		}
+	}
+	for key, count := range after {
+		if before[key] == 0 {
+			diffs = append(diffs, PlaceholderDiff{Kind: PlaceholderAdded, Key: key, After: count})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
@@ -0,0 +1,69 @@
+package docx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiffPlaceholders(t *testing.T) {
+	open := func(body string) *Document {
+		doc := openTestDocument(t, body)
+		return doc
+	}
+	before := open(`<w:p><w:r><w:t>{name} {date} {old}</w:t></w:r></w:p>`)
+	after := open(`<w:p><w:r><w:t>{name} {date}{date} {ne</w:t></w:r><w:r><w:t>w}</w:t></w:r></w:p>`)
+
+	if keys, expected := after.PlaceholderKeys(), []string{"date", "key", "name", "new"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected keys, want=%v, have=%v", expected, keys)
+	}
+
+	diffs := DiffPlaceholders(before, after)
+	expected := []PlaceholderDiff{
+		{Kind: PlaceholderChanged, Key: "date", Before: 1, After: 2},
+		{Kind: PlaceholderAdded, Key: "new", After: 1},
+		{Kind: PlaceholderRemoved, Key: "old", Before: 1},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("unexpected diff\nwant=%v\nhave=%v", expected, diffs)
+	}
+
+	var report []string
+	for _, diff := range diffs {
+		report = append(report, diff.String())
+	}
+	if expected := []string{"~ {date} (1 -> 2)", "+ {new}", "- {old}"}; !reflect.DeepEqual(report, expected) {
+		t.Errorf("unexpected report, want=%q, have=%q", expected, report)
+	}
+
+	if diffs := DiffPlaceholders(before, before); len(diffs) != 0 {
+		t.Errorf("unexpected differences, have=%v", diffs)
+	}
+}
+
+func TestDocument_RequireExactKeys(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml: newTestDocumentXml(`<w:p><w:r><w:t>{name} {date} {name}</w:t></w:r></w:p>`),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the test docx contains a footer with {key}
+	if err := doc.RequireExactKeys(PlaceholderMap{"name": "", "{date}": "", "key": ""}); err != nil {
+		t.Errorf("keys do not match, have=%s", err)
+	}
+
+	err = doc.RequireExactKeys(PlaceholderMap{"name": "", "total": "", "sum": ""})
+	var mismatch *KeysMismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, ErrKeysMismatch) {
+		t.Fatalf("unexpected error, want=%T, have=%v", &KeysMismatchError{}, err)
+	}
+	if !reflect.DeepEqual(mismatch.Missing, []string{"date", "key"}) || !reflect.DeepEqual(mismatch.Extra, []string{"sum", "total"}) {
+		t.Errorf("unexpected mismatch, have=missing %v, extra %v", mismatch.Missing, mismatch.Extra)
+	}
+	expected := ErrKeysMismatch.Error() + ": missing date, key; extra sum, total"
+	if err.Error() != expected {
+		t.Errorf("unexpected error, want=%s, have=%s", expected, err)
+	}
+}
@@ -0,0 +1,65 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidDirective is returned if a template contains a directive which cannot be applied.
+	ErrInvalidDirective = errors.New("invalid template directive")
+
+	// DirectiveRegex matches a directive comment of a template, e.g. '<!-- docx:delimiters=«,» -->'.
+	// The submatches are the name and the value of the directive.
+	DirectiveRegex = regexp.MustCompile(`<!--\s*docx:([\w-]+)=(.*?)\s*-->`)
+)
+
+// applyDirectives applies the directives which are declared in the prolog of the DocumentXml,
+// that is in front of its root element. Templates without directives keep the current settings.
+//
+// The only supported directive is 'delimiters', e.g. '<!-- docx:delimiters=«,» -->'. Its value are the open and close
+// delimiter separated by a comma, each must be a single rune. Just like SetDelimiters, it only changes the delimiters
+// of this document.
+func (d *Document) applyDirectives() error {
+	data := d.files[DocumentXml]
+	prolog := data
+	if root := bytes.Index(data, []byte("<w:document")); root >= 0 {
+		prolog = data[:root]
+	}
+
+	for _, directive := range DirectiveRegex.FindAllSubmatch(prolog, -1) {
+		name, value := string(directive[1]), string(directive[2])
+		switch name {
+		case "delimiters":
+			delims, err := parseDelimiters(value)
+			if err != nil {
+				return err
+			}
+			d.delimiters = delims
+		default:
+			return fmt.Errorf("%w: unknown directive %s", ErrInvalidDirective, name)
+		}
+	}
+	return nil
+}
+
+// parseDelimiters parses the open and close delimiter from a value like '«,»'.
+func parseDelimiters(value string) (delimiters, error) {
+	open, openSize := utf8.DecodeRuneInString(value)
+	if open == utf8.RuneError || len(value) <= openSize || value[openSize] != ',' {
+		return delimiters{}, fmt.Errorf("%w: delimiters must be two runes separated by a comma, have %q", ErrInvalidDirective, value)
+	}
+	rest := value[openSize+1:]
+	close, closeSize := utf8.DecodeRuneInString(rest)
+	if close == utf8.RuneError || len(rest) != closeSize {
+		return delimiters{}, fmt.Errorf("%w: delimiters must be two runes separated by a comma, have %q", ErrInvalidDirective, value)
+	}
+	delims, err := newDelimiters(open, close)
+	if err != nil {
+		return delimiters{}, fmt.Errorf("%w: %s", ErrInvalidDirective, err)
+	}
+	return delims, nil
+}
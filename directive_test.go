@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_DelimitersDirective(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">«name» keeps {name}</w:t></w:r></w:p>`
+	xml := strings.Replace(newTestDocumentXml(body), "<w:document", "<!-- docx:delimiters=«,» --><w:document", 1)
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: xml}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open, close := doc.Delimiters(); open != '«' || close != '»' {
+		t.Fatalf("unexpected delimiters, want=«», have=%c%c", open, close)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Jane keeps {name}"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+
+	// the directive does not affect documents without it
+	other := openTestDocument(t, body)
+	if err := other.ReplaceAll(PlaceholderMap{"name": "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	expected = "«name» keeps Jane"
+	if text := writtenText(t, other, DocumentXml); text != expected {
+		t.Errorf("unexpected text of another document, want=%s, have=%s", expected, text)
+	}
+}
+
+func TestDocument_InvalidDirective(t *testing.T) {
+	for _, directive := range []string{"docx:delimiters=[[,]]", "docx:delimiters=«", "docx:delimiters=«,«", "docx:unknown=1"} {
+		xml := strings.Replace(newTestDocumentXml(""), "<w:document", "<!-- "+directive+" --><w:document", 1)
+		_, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: xml}))
+		if !errors.Is(err, ErrInvalidDirective) {
+			t.Errorf("unexpected error for %s, want=%v, have=%v", directive, ErrInvalidDirective, err)
+		}
+	}
+
+	// directives inside the body are ignored
+	xml := newTestDocumentXml(`<!-- docx:delimiters=«,» --><w:p><w:r><w:t>{key}</w:t></w:r></w:p>`)
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: xml}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := doc.Delimiters(); open != OpenDelimiter {
+		t.Errorf("directives outside of the prolog were not ignored, want=%c, have=%c", OpenDelimiter, open)
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 )
@@ -23,9 +26,24 @@ const (
 
 var (
 	// HeaderPathRegex matches all header files inside the docx-archive.
-	HeaderPathRegex = regexp.MustCompile(`word/header[0-9]*.xml`)
+	// It is anchored in order to not match files like 'word/headerfoo.xmlx' or 'word/_rels/header1.xml.rels'.
+	HeaderPathRegex = regexp.MustCompile(`^word/header[0-9]*\.xml$`)
 	// FooterPathRegex matches all footer files inside the docx-archive.
-	FooterPathRegex = regexp.MustCompile(`word/footer[0-9]*.xml`)
+	// It is anchored in order to not match files like 'word/footerfoo.xmlx' or 'word/_rels/footer1.xml.rels'.
+	FooterPathRegex = regexp.MustCompile(`^word/footer[0-9]*\.xml$`)
+
+	// ErrValueTooLong is returned if a value exceeds the length set with SetMaxValueLength.
+	ErrValueTooLong = errors.New("value exceeds the maximum length")
+
+	// ErrDuplicateFile is returned when opening an archive which contains multiple files with the same name.
+	// Such archives are malformed, consumers differ in which of the files they use.
+	ErrDuplicateFile = errors.New("duplicate file in docx archive")
+
+	// ErrFileNotFound is returned if a file does not exist inside the docx archive.
+	ErrFileNotFound = errors.New("file not found in docx archive")
+
+	// lastRenderedPageBreakRegex matches the page break hints Word stores inside runs
+	lastRenderedPageBreakRegex = regexp.MustCompile(`<w:lastRenderedPageBreak\s*/>`)
 )
 
 // Document exposes the main API of the library.  It represents the actual docx document which is going to be modified.
@@ -48,22 +66,87 @@ type Document struct {
 
 	filePlaceholders map[string][]*Placeholder
 	fileReplacers    map[string]*Replacer
+
+	// preprocessor transforms the parts before they are parsed, see OpenWithPreprocessor
+	preprocessor Preprocessor
+
+	// rawFiles holds all other files of the archive which were modified through the Document API.
+	// Unlike the files above, these are not parsed for runs and are written back as they are.
+	rawFiles FileMap
+
+	// delimiters enclose the placeholders of this document, see SetDelimiters
+	delimiters delimiters
+	// escapeDelimiters enables writing escaped delimiters as literals, see SetEscapeDelimiters
+	escapeDelimiters bool
+	// normalizeWhitespace is passed to the replacers, see SetNormalizeWhitespace
+	normalizeWhitespace bool
+	// matcher is passed to the replacers, see SetMatcher
+	matcher Matcher
+	// removeEmptyRuns is passed to the replacers, see SetRemoveEmptyRuns
+	removeEmptyRuns bool
+	// preWriteHook is invoked for every file on Write(), see SetPreWriteHook
+	preWriteHook PreWriteHook
+	// stripLastRenderedPageBreaks removes rendering hints on Write(), see SetStripLastRenderedPageBreaks
+	stripLastRenderedPageBreaks bool
+	// markFieldsDirty marks all fields as dirty on Write(), see MarkFieldsDirty
+	markFieldsDirty bool
+	// checkStrayDelimiters fails replacing if delimiters are left behind, see SetCheckStrayDelimiters
+	checkStrayDelimiters bool
+	// newlineMode defines how newlines inside the text are written, see SetNewlineMode
+	newlineMode NewlineMode
+	// preserveUnchangedFiles copies unchanged parsed files from the original archive, see SetPreserveUnchangedFiles
+	preserveUnchangedFiles bool
+	// uncompressed stores all files without compression on Write(), see SetUncompressed
+	uncompressed bool
+	// lenientCount disables the error if not all placeholders were replaced, see SetStrictCount
+	lenientCount bool
+	// countMismatches holds the last mismatch of every file which was replaced with lenientCount
+	countMismatches map[string]CountMismatch
+	// recursiveValues enables expanding placeholders inside values, see SetRecursiveValues
+	recursiveValues bool
+	// maxValueLength limits the length of the values in bytes, see SetMaxValueLength
+	maxValueLength int
+	// transformers are applied to the values of their key, see AddTransformer
+	transformers map[string][]Transformer
+	// missingPlaceholderValue replaces all placeholders which are not part of the PlaceholderMap,
+	// see SetMissingPlaceholderValue. If nil, they are kept.
+	missingPlaceholderValue *string
 }
 
 // Open will open and parse the file pointed to by path.
 // The file must be a valid docx file or an error is returned.
+// The file is opened only once, the handle is used to read the archive and closed on Close().
 func Open(path string) (*Document, error) {
 	fh, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open .docx docxFile: %s", err)
 	}
 
-	rc, err := zip.OpenReader(path)
+	doc, err := OpenFileHandle(fh)
+	if err != nil {
+		_ = fh.Close()
+		return nil, err
+	}
+	return doc, nil
+}
+
+// OpenFileHandle creates a Document from an already opened docx file.
+// The archive is read directly from the file (io.ReaderAt), thus it is neither read into memory
+// completely like in OpenBytes nor opened a second time.
+//
+// The Document takes ownership of the file, it is closed on Close(). If an error is returned, the file stays open.
+func OpenFileHandle(f *os.File) (*Document, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat .docx docxFile: %s", err)
+	}
+
+	rc, err := zip.NewReader(f, stat.Size())
 	if err != nil {
 		return nil, fmt.Errorf("unable to open zip reader: %s", err)
 	}
 
-	return newDocument(&rc.Reader, path, fh)
+	return newDocument(rc, f.Name(), f, nil)
 }
 
 // OpenBytes allows to create a Document from a byte slice.
@@ -77,17 +160,20 @@ func OpenBytes(b []byte) (*Document, error) {
 		return nil, fmt.Errorf("unable to open zip reader: %s", err)
 	}
 
-	return newDocument(rc, "", nil)
+	return newDocument(rc, "", nil, nil)
 }
 
 // newDocument will create a new document struct given the zipFile.
 // The params 'path' and 'docxFile' may be empty/nil in case the document is created from a byte source directly.
+// The preprocessor is optional, see OpenWithPreprocessor.
 //
-// newDocument will parse the docx archive and ValidatePositions that at least a 'document.xml' exists.
-// If 'word/document.xml' is missing, an error is returned since the docx cannot be correct.
+// newDocument will parse the docx archive and validate that at least a 'document.xml' exists.
+// If 'word/document.xml' or another mandatory part is missing, an error is returned since the docx cannot be
+// correct, see ValidateArchive.
 // Then all files are parsed for their runs before returning the new document.
-func newDocument(zipFile *zip.Reader, path string, docxFile *os.File) (*Document, error) {
+func newDocument(zipFile *zip.Reader, path string, docxFile *os.File, preprocessor Preprocessor) (*Document, error) {
 	doc := &Document{
+		preprocessor:     preprocessor,
 		docxFile:         docxFile,
 		zipFile:          zipFile,
 		path:             path,
@@ -95,46 +181,100 @@ func newDocument(zipFile *zip.Reader, path string, docxFile *os.File) (*Document
 		runParsers:       make(map[string]*RunParser),
 		filePlaceholders: make(map[string][]*Placeholder),
 		fileReplacers:    make(map[string]*Replacer),
+		rawFiles:         make(FileMap),
+		delimiters:       defaultDelimiters,
 	}
 
-	ResetRunIdCounter()
-	ResetFragmentIdCounter()
-
 	if err := doc.parseArchive(); err != nil {
-		return nil, fmt.Errorf("error parsing document: %s", err)
+		return nil, fmt.Errorf("error parsing document: %w", err)
 	}
 
 	// a valid docx document should really contain a document.xml :)
 	if _, exists := doc.files[DocumentXml]; !exists {
 		return nil, fmt.Errorf("invalid docx archive, %s is missing", DocumentXml)
 	}
+	if err := doc.ValidateArchive(); err != nil {
+		return nil, fmt.Errorf("invalid docx archive: %w", err)
+	}
 
-	// parse all files
-	for name, data := range doc.files {
-
-		// find all runs
-		doc.runParsers[name] = NewRunParser(data)
-		err := doc.runParsers[name].Execute()
-		if err != nil {
-			return nil, err
-		}
+	// the template may declare its own delimiters, they must be known before parsing
+	if err := doc.applyDirectives(); err != nil {
+		return nil, err
+	}
 
-		// parse placeholders and initialize replacers
-		placeholder, err := ParsePlaceholders(doc.runParsers[name].Runs(), data)
-		if err != nil {
+	// parse all files
+	for name := range doc.files {
+		if err := doc.parseFile(name); err != nil {
 			return nil, err
 		}
-		doc.filePlaceholders[name] = placeholder
-		doc.fileReplacers[name] = NewReplacer(data, placeholder)
 	}
 
 	return doc, nil
 }
 
+// parseFile will find all runs and placeholders of the given file and initialize its replacer.
+// If the file was parsed before, the existing state is discarded. This is required whenever
+// the structure of a file is modified outside of the Replacer.
+func (d *Document) parseFile(name string) error {
+	data := d.files[name]
+
+	// find all runs
+	d.runParsers[name] = NewRunParser(data)
+	err := d.runParsers[name].Execute()
+	if err != nil {
+		return err
+	}
+
+	// parse placeholders and initialize replacers
+	result, err := parsePlaceholders(d.runParsers[name].Runs(), data, d.delimiters)
+	if err != nil {
+		return err
+	}
+	placeholder := result.Placeholders
+	d.filePlaceholders[name] = placeholder
+	d.fileReplacers[name] = NewReplacer(data, placeholder)
+	d.fileReplacers[name].delimiters = d.delimiters
+	d.fileReplacers[name].NormalizeWhitespace = d.normalizeWhitespace
+	d.fileReplacers[name].Matcher = d.matcher
+	d.fileReplacers[name].RemoveEmptyRuns = d.removeEmptyRuns
+	return nil
+}
+
+// SetNormalizeWhitespace enables or disables whitespace normalization of placeholders before they are matched
+// against the keys of the PlaceholderMap. This allows to replace placeholders in which Word inserted
+// non-breaking or zero-width spaces, e.g. '{foo\u00a0}' matches the key 'foo'.
+// It is disabled by default, thus placeholders must match exactly.
+func (d *Document) SetNormalizeWhitespace(normalize bool) {
+	d.normalizeWhitespace = normalize
+	for _, replacer := range d.fileReplacers {
+		replacer.NormalizeWhitespace = normalize
+	}
+}
+
+// SetRemoveEmptyRuns enables or disables removing the runs which are left empty after replacing placeholders
+// that span multiple runs. Otherwise, every replaced placeholder may leave empty runs (<w:r><w:t></w:t></w:r>) behind,
+// which bloat the document if it is rendered repeatedly. Runs which were empty in the template are kept.
+// It is disabled by default.
+func (d *Document) SetRemoveEmptyRuns(remove bool) {
+	d.removeEmptyRuns = remove
+	for _, replacer := range d.fileReplacers {
+		replacer.RemoveEmptyRuns = remove
+	}
+}
+
+// SetMissingPlaceholderValue sets the value for all placeholders which are not part of the PlaceholderMap,
+// e.g. 'N/A' or an empty string. It is applied by ReplaceAll, ReplaceAllCollect and ReplaceAllParts
+// after all keys of the PlaceholderMap were replaced. By default, these placeholders are kept as they are.
+func (d *Document) SetMissingPlaceholderValue(value string) {
+	d.missingPlaceholderValue = &value
+}
+
 // ReplaceAll will iterate over all files and perform the replacement according to the PlaceholderMap.
+// Runs nested in shapes are replaced as well, this includes all branches (mc:Choice and mc:Fallback) of
+// alternate content. Thus the document renders the same, regardless of which branch is used.
 func (d *Document) ReplaceAll(placeholderMap PlaceholderMap) error {
 	for name := range d.files {
-		changedBytes, err := d.replace(placeholderMap, name)
+		changedBytes, err := d.replaceWithMissing(placeholderMap, name)
 		if err != nil {
 			return err
 		}
@@ -147,6 +287,58 @@ func (d *Document) ReplaceAll(placeholderMap PlaceholderMap) error {
 	return nil
 }
 
+// ReplacePartial replaces the placeholders of the PlaceholderMap in all files, but keeps all other placeholders.
+// This allows multi-stage pipelines in which every stage fills its own keys, e.g. by writing the document after
+// every stage and opening it again in the next one, or by calling ReplacePartial on the same document repeatedly.
+//
+// Unlike ReplaceAll, the value set by SetMissingPlaceholderValue is not applied and placeholders of the map which
+// could not be replaced are not an error, they are reported by CountMismatches instead.
+// All files are parsed again afterwards, thus the remaining placeholders can be replaced by a later stage.
+// Enable SetEscapeDelimiters, otherwise values which contain delimiters become placeholders of the later stages.
+func (d *Document) ReplacePartial(placeholderMap PlaceholderMap) error {
+	lenientCount := d.lenientCount
+	d.lenientCount = true
+	defer func() { d.lenientCount = lenientCount }()
+
+	for name := range d.files {
+		changedBytes, err := d.replace(placeholderMap, name)
+		if err != nil {
+			return err
+		}
+		if err := d.SetFile(name, changedBytes); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceAllCollect behaves like ReplaceAll but does not stop at the first file which fails.
+// Every file is processed and the errors of all failed files are returned combined (see errors.Join),
+// each prefixed with the name of the file.
+func (d *Document) ReplaceAllCollect(placeholderMap PlaceholderMap) error {
+	var names []string
+	for name := range d.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		changedBytes, err := d.replaceWithMissing(placeholderMap, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if err := d.SetFile(name, changedBytes); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Replace will attempt to replace the given key with the value in every file.
 func (d *Document) Replace(key, value string) error {
 	for name := range d.files {
@@ -168,30 +360,148 @@ func (d *Document) replace(placeholderMap PlaceholderMap, file string) ([]byte,
 	if _, ok := d.runParsers[file]; !ok {
 		return nil, fmt.Errorf("no parser for file %s", file)
 	}
+	if d.recursiveValues {
+		expanded, err := expandValues(placeholderMap, d.delimiters)
+		if err != nil {
+			return nil, err
+		}
+		placeholderMap = expanded
+	}
+	placeholderMap = d.transform(placeholderMap)
+
+	if d.maxValueLength > 0 {
+		for key, value := range placeholderMap {
+			if length := len(fmt.Sprint(value)); length > d.maxValueLength {
+				return nil, fmt.Errorf("%w: the value of %s has %d bytes, the maximum is %d", ErrValueTooLong, key, length, d.maxValueLength)
+			}
+		}
+	}
+
 	placeholderCount := d.countPlaceholders(file, placeholderMap)
 	placeholders := d.filePlaceholders[file]
 	replacer := d.fileReplacers[file]
+	// the replacer is reused by consecutive calls, thus only the placeholders replaced by this call are counted
+	replaceCountBefore := replacer.ReplaceCount
 
+	// delimiters inside the values are escaped in order to survive the unescaping on Write()
+	escapedMap := make(PlaceholderMap, len(placeholderMap))
 	for key, value := range placeholderMap {
-		err := replacer.Replace(key, fmt.Sprint(value))
-		if err != nil {
-			if errors.Is(err, ErrPlaceholderNotFound) {
-				continue
-			} else {
-				return nil, err
-			}
-		}
+		escapedMap[key] = d.markNewlines(d.escapeValue(fmt.Sprint(value)))
+	}
+	if _, err := replacer.ReplaceMap(escapedMap); err != nil {
+		return nil, err
 	}
 
 	// ensure that all placeholders have been replaced
-	if placeholderCount != replacer.ReplaceCount {
-		return nil, fmt.Errorf("not all placeholders were replaced, want=%d, have=%d", placeholderCount, replacer.ReplaceCount)
+	delete(d.countMismatches, file)
+	replaceCount := replacer.ReplaceCount - replaceCountBefore
+	if placeholderCount != replaceCount {
+		if !d.lenientCount {
+			return nil, fmt.Errorf("not all placeholders were replaced, want=%d, have=%d", placeholderCount, replaceCount)
+		}
+		if d.countMismatches == nil {
+			d.countMismatches = make(map[string]CountMismatch)
+		}
+		d.countMismatches[file] = CountMismatch{File: file, Expected: placeholderCount, Replaced: replaceCount}
+	}
+
+	if d.checkStrayDelimiters {
+		if err := d.checkStray(file, replacer.Bytes()); err != nil {
+			return nil, err
+		}
 	}
 
 	d.fileReplacers[file] = replacer
 	d.filePlaceholders[file] = placeholders
 
-	return replacer.Bytes(), nil
+	return d.convertNewlines(file)
+}
+
+// Transformer modifies the value of a placeholder before it is inserted, e.g. strings.ToUpper or strings.TrimSpace.
+type Transformer func(value string) string
+
+// AddTransformer attaches a transformer to the given key (without delimiters). The transformers of a key are
+// applied to its value before it is inserted, in the order in which they were added.
+// Transformers apply to the expanded values if SetRecursiveValues is enabled.
+func (d *Document) AddTransformer(key string, transformer Transformer) {
+	if d.transformers == nil {
+		d.transformers = make(map[string][]Transformer)
+	}
+	d.transformers[key] = append(d.transformers[key], transformer)
+}
+
+// transform returns a copy of the PlaceholderMap in which the transformers are applied to the values.
+// If there are no transformers, the map is returned as it is.
+func (d *Document) transform(placeholderMap PlaceholderMap) PlaceholderMap {
+	if len(d.transformers) == 0 {
+		return placeholderMap
+	}
+	transformed := make(PlaceholderMap, len(placeholderMap))
+	for key, value := range placeholderMap {
+		transformers, exists := d.transformers[key]
+		if !exists {
+			transformed[key] = value
+			continue
+		}
+		text := fmt.Sprint(value)
+		for _, transformer := range transformers {
+			text = transformer(text)
+		}
+		transformed[key] = text
+	}
+	return transformed
+}
+
+// SetMaxValueLength limits the length of the values of the PlaceholderMap to the given amount of bytes.
+// Replacing fails with ErrValueTooLong before anything is replaced if a value is longer, which protects against
+// accidentally inserting huge values. The limit applies to the values after they were expanded and transformed.
+// A limit of zero, the default, disables the check.
+func (d *Document) SetMaxValueLength(length int) {
+	d.maxValueLength = length
+}
+
+// SetStrictCount enables or disables the check that all occurrences of the keys of the PlaceholderMap were replaced.
+// By default it is enabled and replacing fails if placeholders could not be replaced, e.g. because they are
+// malformed. If disabled, the replacement continues and the mismatches are available through CountMismatches.
+func (d *Document) SetStrictCount(strict bool) {
+	d.lenientCount = !strict
+}
+
+// CountMismatch describes a file in which not all occurrences of the keys of the PlaceholderMap were replaced.
+type CountMismatch struct {
+	File string
+	// Expected is the amount of occurrences of the keys inside the text of the file.
+	Expected int
+	// Replaced is the amount of replaced placeholders.
+	Replaced int
+}
+
+// CountMismatches returns the mismatches of the last replacement of every file, sorted by the file name.
+// Mismatches are only recorded if SetStrictCount is disabled.
+func (d *Document) CountMismatches() []CountMismatch {
+	var mismatches []CountMismatch
+	for _, mismatch := range d.countMismatches {
+		mismatches = append(mismatches, mismatch)
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].File < mismatches[j].File
+	})
+	return mismatches
+}
+
+// replaceWithMissing works like replace, but afterwards all remaining placeholders of the file are replaced with
+// the value set by SetMissingPlaceholderValue, if any.
+func (d *Document) replaceWithMissing(placeholderMap PlaceholderMap, file string) ([]byte, error) {
+	changedBytes, err := d.replace(placeholderMap, file)
+	if err != nil || d.missingPlaceholderValue == nil {
+		return changedBytes, err
+	}
+
+	replacer := d.fileReplacers[file]
+	if _, err := replacer.ReplaceRemaining(d.markNewlines(d.escapeValue(*d.missingPlaceholderValue))); err != nil {
+		return nil, err
+	}
+	return d.convertNewlines(file)
 }
 
 // Runs returns all runs from all parsed files.
@@ -202,6 +512,51 @@ func (d *Document) Runs() (runs []*Run) {
 	return runs
 }
 
+// RunStats returns the amount of runs and the amount of runs with text of the given file.
+// The numbers describe the complexity of the file and are useful for profiling and reporting issues.
+// If the file is unknown, zero is returned for both.
+func (d *Document) RunStats(fileName string) (total, withText int) {
+	parser, exists := d.runParsers[fileName]
+	if !exists {
+		return 0, 0
+	}
+	return len(parser.Runs()), len(parser.Runs().WithText())
+}
+
+// TotalRunStats returns the summed up RunStats of all parsed files.
+func (d *Document) TotalRunStats() (total, withText int) {
+	for name := range d.runParsers {
+		fileTotal, fileWithText := d.RunStats(name)
+		total += fileTotal
+		withText += fileWithText
+	}
+	return total, withText
+}
+
+// parsedFiles returns the names of all parsed files in a stable order: the document, headers and footers.
+func (d *Document) parsedFiles() []string {
+	files := append([]string{DocumentXml}, d.headerFiles...)
+	return append(files, d.footerFiles...)
+}
+
+// allParsedFiles returns the names of the parsed files ordered like parsedFiles,
+// followed by all other parsed parts (see ReplaceAllParts) sorted by name.
+func (d *Document) allParsedFiles() []string {
+	files := d.parsedFiles()
+	visited := make(map[string]bool, len(files))
+	for _, file := range files {
+		visited[file] = true
+	}
+	var parts []string
+	for file := range d.filePlaceholders {
+		if !visited[file] {
+			parts = append(parts, file)
+		}
+	}
+	sort.Strings(parts)
+	return append(files, parts...)
+}
+
 // Placeholders returns all placeholders from the docx document.
 func (d *Document) Placeholders() (placeholders []*Placeholder) {
 	for _, p := range d.filePlaceholders {
@@ -210,23 +565,76 @@ func (d *Document) Placeholders() (placeholders []*Placeholder) {
 	return placeholders
 }
 
+// IteratePlaceholders calls fn for every placeholder of the document together with the file it belongs to,
+// until fn returns false. Unlike Placeholders, no slice of all placeholders is allocated.
+// The document is visited first, followed by the headers, the footers and all other parsed parts sorted by name.
+func (d *Document) IteratePlaceholders(fn func(file string, p *Placeholder) bool) {
+	for _, file := range d.allParsedFiles() {
+		for _, placeholder := range d.filePlaceholders[file] {
+			if !fn(file, placeholder) {
+				return
+			}
+		}
+	}
+}
+
 // countPlaceholders will return the total count of placeholders from the placeholderMap in the given data.
 // Reoccurring placeholders are also counted multiple times.
+// Only whole delimited placeholders are counted, so e.g. '{total}' is not counted inside the escaped literal '{{total}}'.
 func (d *Document) countPlaceholders(file string, placeholderMap PlaceholderMap) int {
 	data := d.GetFile(file)
 	plaintext := d.stripXmlTags(string(data))
-	var placeholderCount int
+	occurrences := countDelimitedPlaceholders(plaintext, d.delimiters)
+	matcher := effectiveMatcher(d.matcher, d.normalizeWhitespace)
+	exact := d.matcher == nil && !d.normalizeWhitespace
+
+	keys := make(map[string]bool, len(placeholderMap))
 	for key := range placeholderMap {
-		placeholder := AddPlaceholderDelimiter(key)
+		keys[d.delimiters.add(key)] = true
+	}
 
-		count := strings.Count(plaintext, placeholder)
-		if count > 0 {
-			placeholderCount += count
+	// every placeholder is counted once, even if it is matched by multiple keys
+	var placeholderCount int
+	for placeholder, count := range occurrences {
+		if exact {
+			if keys[placeholder] {
+				placeholderCount += count
+			}
+			continue
+		}
+		for key := range keys {
+			if matcher.Match(d.delimiters.withDefaults(placeholder), d.delimiters.withDefaults(key)) {
+				placeholderCount += count
+				break
+			}
 		}
 	}
 	return placeholderCount
 }
 
+// countDelimitedPlaceholders returns the number of occurrences of every delimited placeholder inside the given text.
+// Escaped delimiters are ignored and if placeholders are nested, only the innermost one is counted.
+func countDelimitedPlaceholders(text string, delims delimiters) map[string]int {
+	occurrences := make(map[string]int)
+	openPos, closePos := delimiterPositions(text, false, delims)
+
+	lastOpen := -1
+	for len(openPos) > 0 || len(closePos) > 0 {
+		if len(openPos) > 0 && (len(closePos) == 0 || openPos[0] < closePos[0]) {
+			lastOpen = openPos[0]
+			openPos = openPos[1:]
+			continue
+		}
+		if lastOpen >= 0 {
+			end := closePos[0] + utf8.RuneLen(delims.close)
+			occurrences[text[lastOpen:end]]++
+			lastOpen = -1
+		}
+		closePos = closePos[1:]
+	}
+	return occurrences
+}
+
 // stripXmlTags is a stdlib way of stripping out all xml tags using the html.Tokenizer.
 // The returned string will be everything except the tags.
 func (d *Document) stripXmlTags(data string) string {
@@ -272,7 +680,38 @@ func (d *Document) SetFile(fileName string, fileBytes []byte) error {
 	return nil
 }
 
+// readRawFile returns the current content of any file inside the archive.
+// Modified files take precedence over the original content of the zip archive.
+// If the file does not exist, ErrFileNotFound is returned.
+func (d *Document) readRawFile(fileName string) ([]byte, error) {
+	if f, exists := d.files[fileName]; exists {
+		return f, nil
+	}
+	if f, exists := d.rawFiles[fileName]; exists {
+		return f, nil
+	}
+	for _, file := range d.zipFile.File {
+		if file.Name != fileName {
+			continue
+		}
+		readCloser, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %s", fileName, err)
+		}
+		defer readCloser.Close()
+		return ioutil.ReadAll(readCloser)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFileNotFound, fileName)
+}
+
+// setRawFile stores the modified content of a file which is not parsed for runs.
+// The content is written into the archive instead of the original file on Write().
+func (d *Document) setRawFile(fileName string, fileBytes []byte) {
+	d.rawFiles[fileName] = fileBytes
+}
+
 // parseArchive will go through the docx zip archive and read them into the FileMap.
+// Archives containing the same file multiple times are rejected with ErrDuplicateFile.
 // Files inside the FileMap are those which can be modified by the lib.
 // Currently not all files are read, only:
 // 	- word/document.xml
@@ -289,10 +728,16 @@ func (d *Document) parseArchive() error {
 		if err != nil {
 			return nil
 		}
-		return fileBytes
+		return d.preprocess(file.Name, fileBytes)
 	}
 
+	seen := make(map[string]bool)
 	for _, file := range d.zipFile.File {
+		if seen[file.Name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateFile, file.Name)
+		}
+		seen[file.Name] = true
+
 		if file.Name == DocumentXml {
 			d.files[DocumentXml] = readZipFile(file)
 		}
@@ -311,23 +756,105 @@ func (d *Document) parseArchive() error {
 // WriteToFile will write the document to a new file.
 // It is important to note that the target file cannot be the same as the path of this document.
 // If the path is not yet created, the function will attempt to MkdirAll() before creating the file.
+//
+// The document is written into a temporary file next to the target first, which is renamed to the target
+// once writing succeeded. Thus an existing file is never left truncated or corrupt if writing fails.
 func (d *Document) WriteToFile(file string) error {
 	if file == d.path {
 		return fmt.Errorf("WriteToFile cannot write into the original docx archive while it'str open")
 	}
+	return d.writeFile(file)
+}
 
-	err := os.MkdirAll(filepath.Dir(file), 0755)
+// WriteToFileBackup writes the document to the file just like WriteToFile, but keeps an existing file as backup.
+// The document is written into a temporary file first. Once that succeeded, an existing file is renamed to the same
+// path with the suffix '.bak', replacing an older backup, and the temporary file takes its place. Thus there is a
+// file at the path at all times and the file may also be the path of this document, the original is kept as backup.
+// Note that open files cannot be renamed on Windows, there the file must not be the path of an open document.
+func (d *Document) WriteToFileBackup(file string) error {
+	backup := file + ".bak"
+	if _, err := os.Stat(file); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("unable to check %s: %s", file, err)
+		}
+		return d.writeFile(file)
+	}
+
+	tmp, err := d.writeTempFile(file)
 	if err != nil {
-		return fmt.Errorf("unable to ensure path directories: %s", err)
+		return err
+	}
+	// removing fails once the file was renamed, which is fine
+	defer os.Remove(tmp)
+
+	// the original archive stays readable after renaming, it is read through the open file handle
+	if err := os.Rename(file, backup); err != nil {
+		return fmt.Errorf("unable to create backup %s: %s", backup, err)
 	}
+	if err := os.Rename(tmp, file); err != nil {
+		if restoreErr := os.Rename(backup, file); restoreErr != nil {
+			return fmt.Errorf("unable to move temporary file to %s: %s, unable to restore backup %s: %s", file, err, backup, restoreErr)
+		}
+		return fmt.Errorf("unable to move temporary file to %s: %s", file, err)
+	}
+	return nil
+}
 
-	target, err := os.Create(file)
+// writeFile writes the document into a temporary file next to the target, which is renamed to the target
+// once writing succeeded.
+func (d *Document) writeFile(file string) error {
+	tmp, err := d.writeTempFile(file)
 	if err != nil {
 		return err
 	}
-	defer target.Close()
+	// removing fails once the file was renamed, which is fine
+	defer os.Remove(tmp)
+
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("unable to move temporary file to %s: %s", file, err)
+	}
+	return nil
+}
+
+// writeTempFile writes the document into a new temporary file next to the target and returns its path.
+// The temporary file has the mode of the target if it exists. If writing fails, it is removed again.
+func (d *Document) writeTempFile(file string) (string, error) {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return "", fmt.Errorf("unable to ensure path directories: %s", err)
+	}
 
-	return d.Write(target)
+	// the temporary file must be in the same directory, renaming is only atomic on the same filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary file: %s", err)
+	}
+
+	if err := d.Write(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("unable to sync temporary file: %s", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("unable to close temporary file: %s", err)
+	}
+
+	// temporary files are only accessible by the owner, keep the mode of an existing file instead
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(file); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("unable to set file mode: %s", err)
+	}
+	return tmp.Name(), nil
 }
 
 // Write is responsible for assembling a new .docx docxFile using the modified data as well as all remaining files.
@@ -335,24 +862,41 @@ func (d *Document) WriteToFile(file string) error {
 // Files which cannot be modified through this lib will just be read from the original docx and copied into the writer.
 func (d *Document) Write(writer io.Writer) error {
 	zipWriter := zip.NewWriter(writer)
-	defer zipWriter.Close()
 
 	// writeModifiedFile will check if the given zipFile is a file which was modified and writes it.
 	// If the file is not one of the modified files, false is returned.
 	writeModifiedFile := func(writer io.Writer, zipFile *zip.File) (bool, error) {
 		isModified := d.isModifiedFile(zipFile.Name)
-		if !isModified {
+		if !isModified || (d.preserveUnchangedFiles && d.isUnchangedFile(zipFile)) {
 			return false, nil
 		}
-		if err := d.files.Write(writer, zipFile.Name); err != nil {
+		// escaped delimiters are only unescaped in the output, the files itself need to keep them
+		// since they would be detected as placeholders otherwise.
+		data, isRaw := d.rawFiles[zipFile.Name]
+		if !isRaw {
+			data = d.outputFile(zipFile.Name)
+		}
+		// modified XML parts must not lose their declaration, e.g. if they were replaced using SetFile
+		data, err := d.preWrite(zipFile.Name, withXmlDeclaration(zipFile.Name, data))
+		if err != nil {
+			return false, err
+		}
+		if err := (FileMap{zipFile.Name: data}).Write(writer, zipFile.Name); err != nil {
 			return false, fmt.Errorf("unable to writeFile %s: %s", zipFile.Name, err)
 		}
 		return true, nil
 	}
 
 	// write all files into the zip archive (docx-file)
-	for _, zipFile := range d.zipFile.File {
-		fw, err := zipWriter.Create(zipFile.Name)
+	for _, zipFile := range d.orderedZipFiles() {
+		// The sizes are not known upfront, archive/zip will switch to zip64 records on its own
+		// if an entry (e.g. large embedded media) or the archive itself exceeds the zip32 limits.
+		// Every entry is decompressed and written again, thus the method of the original entry does not matter.
+		fw, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     zipFile.Name,
+			Method:   d.compressionMethod(),
+			Modified: zipFile.Modified,
+		})
 		if err != nil {
 			return fmt.Errorf("unable to create writer: %s", err)
 		}
@@ -371,7 +915,19 @@ func (d *Document) Write(writer io.Writer) error {
 		if err != nil {
 			return fmt.Errorf("unable to open %s: %s", zipFile.Name, err)
 		}
-		_, err = fw.Write(readBytes(readCloser))
+		// the hook needs the whole content, otherwise it is streamed instead of buffered since media files may be huge
+		var reader io.Reader = readCloser
+		if d.preWriteHook != nil {
+			data, err := ioutil.ReadAll(readCloser)
+			if err != nil {
+				return fmt.Errorf("unable to read %s: %s", zipFile.Name, err)
+			}
+			if data, err = d.preWrite(zipFile.Name, data); err != nil {
+				return err
+			}
+			reader = bytes.NewReader(data)
+		}
+		_, err = io.Copy(fw, reader)
 		if err != nil {
 			return fmt.Errorf("unable to writeFile zipFile %s: %s", zipFile.Name, err)
 		}
@@ -380,20 +936,195 @@ func (d *Document) Write(writer io.Writer) error {
 			return fmt.Errorf("unable to close reader for %s: %s", zipFile.Name, err)
 		}
 	}
+
+	// parts which were added by us do not exist in the original archive
+	if err := d.writeAddedFiles(zipWriter); err != nil {
+		return err
+	}
+
+	// closing writes the central directory, an error here means that the archive is broken
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("unable to close zip writer: %s", err)
+	}
 	return nil
 }
 
-// isModifiedFile will look through all modified files and check if the searchFileName exists
-func (d *Document) isModifiedFile(searchFileName string) bool {
-	allFiles := append(d.headerFiles, d.footerFiles...)
-	allFiles = append(allFiles, DocumentXml)
+// outputFile returns the content of the parsed file as it is written into the archive.
+func (d *Document) outputFile(name string) []byte {
+	data := d.files[name]
+	if d.escapeDelimiters {
+		data = unescapeTextRuns(data, d.delimiters)
+	}
+	if d.stripLastRenderedPageBreaks {
+		data = lastRenderedPageBreakRegex.ReplaceAll(data, nil)
+	}
+	if d.markFieldsDirty {
+		data = markFieldsDirty(data)
+	}
+	return data
+}
+
+// SetStripLastRenderedPageBreaks enables or disables removing the <w:lastRenderedPageBreak/> hints on Write().
+// Word stores where the pages broke when the document was saved the last time. If the replaced values change the
+// length of the text a lot, these stale hints may cause an odd initial rendering until Word repaginated.
+// Removing them is safe, Word recalculates the page breaks anyway. It is disabled by default.
+func (d *Document) SetStripLastRenderedPageBreaks(strip bool) {
+	d.stripLastRenderedPageBreaks = strip
+}
+
+// SetUncompressed enables or disables writing all files without compression (zip.Store) on Write().
+// This is meant for debugging, the XML of the written document can be diffed without unzipping it.
+// Files are compressed (zip.Deflate) by default.
+func (d *Document) SetUncompressed(uncompressed bool) {
+	d.uncompressed = uncompressed
+}
+
+// compressionMethod returns the zip method used to write the files.
+func (d *Document) compressionMethod() uint16 {
+	if d.uncompressed {
+		return zip.Store
+	}
+	return zip.Deflate
+}
+
+// PreWriteHook is invoked with the name and content of every file before it is written into the archive.
+// The returned bytes are written instead, returning an error aborts the write.
+type PreWriteHook func(fileName string, data []byte) ([]byte, error)
+
+// SetPreWriteHook sets a hook which is invoked for every file on Write(), e.g. to inspect or post-process the XML.
+// Setting a nil hook removes it.
+func (d *Document) SetPreWriteHook(hook PreWriteHook) {
+	d.preWriteHook = hook
+}
+
+// preWrite passes the file through the pre-write hook, if one is set.
+func (d *Document) preWrite(fileName string, data []byte) ([]byte, error) {
+	if d.preWriteHook == nil {
+		return data, nil
+	}
+	data, err := d.preWriteHook(fileName, data)
+	if err != nil {
+		return nil, fmt.Errorf("pre-write hook failed for %s: %w", fileName, err)
+	}
+	return data, nil
+}
+
+// orderedZipFiles returns the files of the original archive in the order in which they are written.
+// Following the package convention, the ContentTypesXml is written first and the package relationships
+// (_rels/.rels) second since some strict consumers expect them there. All other files keep their order.
+func (d *Document) orderedZipFiles() []*zip.File {
+	leading := []string{ContentTypesXml, PackageRelationshipsXml}
+
+	var files []*zip.File
+	for _, name := range leading {
+		for _, zipFile := range d.zipFile.File {
+			if zipFile.Name == name {
+				files = append(files, zipFile)
+				break
+			}
+		}
+	}
+	for _, zipFile := range d.zipFile.File {
+		if zipFile.Name != ContentTypesXml && zipFile.Name != PackageRelationshipsXml {
+			files = append(files, zipFile)
+		}
+	}
+	return files
+}
 
-	for _, file := range allFiles {
-		if searchFileName == file {
-			return true
+// WriteTo implements io.WriterTo, it writes the document just like Write and returns the number of bytes written.
+func (d *Document) WriteTo(writer io.Writer) (int64, error) {
+	counter := &countingWriter{writer: writer}
+	err := d.Write(counter)
+	return counter.count, err
+}
+
+// countingWriter counts the bytes written into the underlying writer.
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+// writeAddedFiles writes all files which do not exist in the original archive, sorted by their name.
+func (d *Document) writeAddedFiles(zipWriter *zip.Writer) error {
+	existing := make(map[string]bool)
+	for _, zipFile := range d.zipFile.File {
+		existing[zipFile.Name] = true
+	}
+	var added []string
+	for name := range d.rawFiles {
+		if !existing[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range d.files {
+		if !existing[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+
+	for _, name := range added {
+		fw, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   d.compressionMethod(),
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create writer: %s", err)
+		}
+		data, isRaw := d.rawFiles[name]
+		if !isRaw {
+			data = d.outputFile(name)
 		}
+		data, err = d.preWrite(name, withXmlDeclaration(name, data))
+		if err != nil {
+			return err
+		}
+		if err := (FileMap{name: data}).Write(fw, name); err != nil {
+			return fmt.Errorf("unable to writeFile %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// SetPreserveUnchangedFiles enables or disables copying parsed files which were not changed (e.g. headers and footers
+// without any replaced placeholder) from the original archive on Write(), instead of writing them back.
+// This guarantees that their content stays byte-identical. Since they are not written back, the output options
+// (e.g. SetEscapeDelimiters or SetStripLastRenderedPageBreaks) are not applied to them. It is disabled by default.
+func (d *Document) SetPreserveUnchangedFiles(preserve bool) {
+	d.preserveUnchangedFiles = preserve
+}
+
+// isUnchangedFile returns true if the zipFile is a parsed file whose content equals the original content.
+func (d *Document) isUnchangedFile(zipFile *zip.File) bool {
+	data, exists := d.files[zipFile.Name]
+	if _, isRaw := d.rawFiles[zipFile.Name]; !exists || isRaw || uint64(len(data)) != zipFile.UncompressedSize64 {
+		return false
+	}
+	readCloser, err := zipFile.Open()
+	if err != nil {
+		return false
+	}
+	defer readCloser.Close()
+	original, err := ioutil.ReadAll(readCloser)
+	return err == nil && bytes.Equal(original, data)
+}
+
+// isModifiedFile will look through all modified files and check if the searchFileName exists
+func (d *Document) isModifiedFile(searchFileName string) bool {
+	if _, exists := d.rawFiles[searchFileName]; exists {
+		return true
 	}
-	return false
+	// besides the document, headers and footers, this includes the parts parsed by ReplaceAllParts
+	_, exists := d.files[searchFileName]
+	return exists
 }
 
 // Close will close everything :)
@@ -1,6 +1,17 @@
 package docx
 
-import "testing"
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func BenchmarkDocument_ReplaceAll(b *testing.B) {
 	for n := 0; n < b.N; n++ {
@@ -33,3 +44,735 @@ func BenchmarkDocument_ReplaceAll(b *testing.B) {
 		}
 	}
 }
+
+// manyPlaceholdersDocx returns a document with n placeholders which are each split into two runs,
+// together with the PlaceholderMap to replace them and the expected text.
+func manyPlaceholdersDocx(t testing.TB, n int) ([]byte, PlaceholderMap, string) {
+	var body, expected strings.Builder
+	replaceMap := make(PlaceholderMap, n)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&body, `<w:p><w:r><w:t xml:space="preserve">Item %d: {item-</w:t></w:r><w:r><w:t>%d}</w:t></w:r></w:p>`, i, i)
+		replaceMap[fmt.Sprintf("item-%d", i)] = fmt.Sprintf("value %d", i)
+		fmt.Fprintf(&expected, "Item %d: value %d", i, i)
+	}
+	return newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml(body.String())}), replaceMap, expected.String()
+}
+
+func TestDocument_ReplaceAll_ManyPlaceholders(t *testing.T) {
+	docx, replaceMap, expected := manyPlaceholdersDocx(t, 500)
+	doc, err := OpenBytes(docx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(replaceMap); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+	if err := doc.ValidateOutput(); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkDocument_ReplaceAll_ManyPlaceholders(b *testing.B) {
+	docx, replaceMap, _ := manyPlaceholdersDocx(b, 2000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		doc, err := OpenBytes(docx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := doc.ReplaceAll(replaceMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDocument_WriteEntryOrder(t *testing.T) {
+	template, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	contentTypes, err := template.readRawFile(ContentTypesXml)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// files passed to newTestDocx are added to the end of the archive
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{ContentTypesXml: string(contentTypes)}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		t.Error("unable to write", err)
+		return
+	}
+	written, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Error("written docx is not a valid zip archive", err)
+		return
+	}
+
+	if name := written.File[0].Name; name != ContentTypesXml {
+		t.Errorf("unexpected first entry, want=%s, have=%s", ContentTypesXml, name)
+	}
+	if name := written.File[1].Name; name != PackageRelationshipsXml {
+		t.Errorf("unexpected second entry, want=%s, have=%s", PackageRelationshipsXml, name)
+	}
+	if len(written.File) != len(doc.zipFile.File) {
+		t.Errorf("unexpected amount of entries, want=%d, have=%d", len(doc.zipFile.File), len(written.File))
+	}
+}
+
+func TestDocument_WriteZip64(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping zip64 test in short mode")
+	}
+
+	// more than 65535 entries require the zip64 end of central directory records
+	expectedEntries := 70000
+
+	template, err := zip.OpenReader("./test/template.docx")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer template.Close()
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range template.File {
+		if err := zipWriter.Copy(file); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	for i := len(template.File); i < expectedEntries; i++ {
+		fw, err := zipWriter.Create(fmt.Sprintf("word/media/image%d.png", i))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = fw.Write([]byte{0x89, 'P', 'N', 'G'})
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	doc, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Error("failed to open zip64 docx", err)
+		return
+	}
+
+	out := new(bytes.Buffer)
+	if err := doc.Write(out); err != nil {
+		t.Error("unable to write", err)
+		return
+	}
+
+	written, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Error("written docx is not a valid zip archive", err)
+		return
+	}
+	if len(written.File) != expectedEntries {
+		t.Errorf("not all entries were written, want=%d, have=%d", expectedEntries, len(written.File))
+	}
+}
+
+func TestHeaderFooterPathRegex(t *testing.T) {
+	tests := []struct {
+		path   string
+		header bool
+		footer bool
+	}{
+		{path: "word/header.xml", header: true},
+		{path: "word/header1.xml", header: true},
+		{path: "word/header12.xml", header: true},
+		{path: "word/footer1.xml", footer: true},
+		{path: "word/headerfoo.xml"},
+		{path: "word/header1.xmlx"},
+		{path: "word/header1xxml"},
+		{path: "word/footer1.xml.bak"},
+		{path: "word/_rels/header1.xml.rels"},
+		{path: "customXml/word/header1.xml"},
+	}
+
+	for _, tt := range tests {
+		if is := HeaderPathRegex.MatchString(tt.path); is != tt.header {
+			t.Errorf("HeaderPathRegex.MatchString(%s), want=%t, have=%t", tt.path, tt.header, is)
+		}
+		if is := FooterPathRegex.MatchString(tt.path); is != tt.footer {
+			t.Errorf("FooterPathRegex.MatchString(%s), want=%t, have=%t", tt.path, tt.footer, is)
+		}
+	}
+}
+
+// newTestDocx returns a docx archive based on ./test/template.docx.
+// The given files are added to the archive, replacing files of the template with the same name.
+func newTestDocx(t testing.TB, files map[string]string) []byte {
+	template, err := zip.OpenReader("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer template.Close()
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range template.File {
+		if _, replaced := files[file.Name]; replaced {
+			continue
+		}
+		if err := zipWriter.Copy(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		fw, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newTestDocumentXml wraps the given body content into a word/document.xml.
+func newTestDocumentXml(body string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<w:body>` + body + `</w:body></w:document>`
+}
+
+// openTestDocument opens the test docx with a word/document.xml containing the given body content.
+func openTestDocument(t testing.TB, body string) *Document {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml(body)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// writtenText writes the document and returns the plaintext of all text-runs of the given file.
+func writtenText(t testing.TB, doc *Document, file string) string {
+	data := writtenFile(t, doc, file)
+	if err := xml.Unmarshal(data, new(interface{})); err != nil {
+		t.Fatalf("written %s is not valid xml: %s", file, err)
+	}
+	var text string
+	for _, match := range TextRunRegex.FindAllSubmatch(data, -1) {
+		text += html.UnescapeString(string(match[2]))
+	}
+	return text
+}
+
+// writtenFile writes the document and returns the content of the given file inside the written archive.
+func writtenFile(t testing.TB, doc *Document, file string) []byte {
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		t.Fatal("unable to write", err)
+	}
+	written, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal("written docx is not a valid zip archive", err)
+	}
+	for _, zipFile := range written.File {
+		if zipFile.Name != file {
+			continue
+		}
+		readCloser, err := zipFile.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := readBytes(readCloser)
+		_ = readCloser.Close()
+		return data
+	}
+	t.Fatalf("%s is missing in the written docx", file)
+	return nil
+}
+
+func TestDocument_RunStats(t *testing.T) {
+	body := `<w:p><w:r><w:t>foo</w:t></w:r><w:r><w:br/></w:r><w:r/></w:p>`
+	doc := openTestDocument(t, body)
+
+	total, withText := doc.RunStats(DocumentXml)
+	if total != 3 || withText != 1 {
+		t.Errorf("unexpected run stats, want=(3, 1), have=(%d, %d)", total, withText)
+	}
+
+	headerTotal, headerWithText := doc.RunStats("word/header1.xml")
+	footerTotal, footerWithText := doc.RunStats("word/footer1.xml")
+	allTotal, allWithText := doc.TotalRunStats()
+	if allTotal != total+headerTotal+footerTotal || allWithText != withText+headerWithText+footerWithText {
+		t.Errorf("total run stats do not sum up the stats of all files")
+	}
+
+	if total, withText := doc.RunStats("word/unknown.xml"); total != 0 || withText != 0 {
+		t.Errorf("expected no runs for unknown file")
+	}
+}
+
+func TestDocument_CountPlaceholders(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">{total} {total_net} {{total}} {total}}}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{tot</w:t></w:r><w:r><w:t>al_net}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	tests := []struct {
+		placeholderMap PlaceholderMap
+		matcher        Matcher
+		expected       int
+	}{
+		{PlaceholderMap{"total": ""}, nil, 2},
+		{PlaceholderMap{"total_net": ""}, nil, 2},
+		{PlaceholderMap{"total": "", "total_net": ""}, nil, 4},
+		{PlaceholderMap{"tot": ""}, nil, 0},
+		// placeholders matching multiple keys are counted once
+		{PlaceholderMap{"total": "", "{total}": ""}, nil, 2},
+		{PlaceholderMap{"total": "", "TOTAL": ""}, CaseInsensitiveMatcher, 2},
+		{PlaceholderMap{"total.*": "", "total_net": ""}, RegexMatcher(), 4},
+	}
+	for _, tt := range tests {
+		doc.SetMatcher(tt.matcher)
+		if count := doc.countPlaceholders(DocumentXml, tt.placeholderMap); count != tt.expected {
+			t.Errorf("unexpected count for %v, want=%d, have=%d", tt.placeholderMap, tt.expected, count)
+		}
+	}
+	doc.SetMatcher(nil)
+
+	if err := doc.ReplaceAll(PlaceholderMap{"total": "100", "total_net": "80"}); err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+	expected := "100 80 {{total}} 100}}80"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
+
+func TestDocument_ReplaceAllCollect(t *testing.T) {
+	// nested placeholders are skipped by the parser, thus '{bar}' cannot be replaced
+	paragraph := `<w:p><w:r><w:t>{foo{bar}x}</w:t></w:r></w:p>`
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + paragraph + `</w:hdr>`
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml:        newTestDocumentXml(paragraph),
+		"word/header1.xml": header,
+	}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = doc.ReplaceAllCollect(PlaceholderMap{"bar": "baz"})
+	if err == nil {
+		t.Error("expected replacing to fail")
+		return
+	}
+	for _, file := range []string{DocumentXml, "word/header1.xml"} {
+		if !strings.Contains(err.Error(), file+": ") {
+			t.Errorf("error of %s was not reported, have=%s", file, err)
+		}
+	}
+}
+
+func TestDocument_SetPreWriteHook(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	doc.SetPreWriteHook(func(fileName string, data []byte) ([]byte, error) {
+		seen[fileName] = true
+		if fileName == DocumentXml {
+			return bytes.Replace(data, []byte("{key}"), []byte("hooked"), -1), nil
+		}
+		return data, nil
+	})
+	if text := writtenText(t, doc, DocumentXml); !strings.Contains(text, "hooked") {
+		t.Error("expected the hook to modify the document")
+	}
+	for _, file := range doc.zipFile.File {
+		if !seen[file.Name] {
+			t.Errorf("expected hook to be invoked for %s", file.Name)
+		}
+	}
+
+	doc.SetPreWriteHook(func(fileName string, data []byte) ([]byte, error) {
+		return nil, fmt.Errorf("rejected")
+	})
+	if err := doc.Write(new(bytes.Buffer)); err == nil {
+		t.Error("expected an error of the hook to abort writing")
+	}
+}
+
+func TestOpenFileHandle(t *testing.T) {
+	f, err := os.Open("./test/template.docx")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	doc, err := OpenFileHandle(f)
+	if err != nil {
+		t.Error("opening file handle failed", err)
+		return
+	}
+
+	if err := doc.Replace("key", "value"); err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+	if text := writtenText(t, doc, DocumentXml); !strings.Contains(text, "value") {
+		t.Error("expected placeholder to be replaced")
+	}
+
+	doc.Close()
+	if _, err := f.Stat(); err == nil {
+		t.Error("expected the file handle to be closed")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	doc, err := Open("./test/template.docx")
+	if err != nil {
+		t.Error("opening failed", err)
+		return
+	}
+	if doc.path != "./test/template.docx" {
+		t.Errorf("unexpected path, want=%s, have=%s", "./test/template.docx", doc.path)
+	}
+	doc.Close()
+	if _, err := doc.docxFile.Stat(); err == nil {
+		t.Error("expected the file to be closed")
+	}
+
+	if _, err := Open("./test/placeholder.xml"); err == nil {
+		t.Error("expected opening a file which is not a zip archive to fail")
+	}
+}
+
+func TestOpen_StoredDocumentXml(t *testing.T) {
+	// the fixture equals template.docx, except that the document.xml is stored instead of deflated
+	doc, err := Open("./test/stored.docx")
+	if err != nil {
+		t.Fatal("opening failed", err)
+	}
+	defer doc.Close()
+	for _, zipFile := range doc.zipFile.File {
+		if zipFile.Name == DocumentXml && zipFile.Method != zip.Store {
+			t.Fatalf("unexpected method of %s in the fixture, want=%d, have=%d", DocumentXml, zip.Store, zipFile.Method)
+		}
+	}
+	if err := ValidatePositions(doc.files[DocumentXml], doc.runParsers[DocumentXml].Runs()); err != nil {
+		t.Fatal("unexpected positions of the stored document", err)
+	}
+
+	if err := doc.Replace("key", "value"); err != nil {
+		t.Fatal("replacing failed", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal("unable to write", err)
+	}
+
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal("unable to open the written document", err)
+	}
+	if text := written.GetFile(DocumentXml); !bytes.Contains(text, []byte("value")) {
+		t.Error("expected placeholder to be replaced")
+	}
+}
+
+func TestDocument_WriteToFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "out", "replaced.docx")
+
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := doc.WriteToFile(target); err != nil {
+		t.Error("writing failed", err)
+		return
+	}
+	written, err := Open(target)
+	if err != nil {
+		t.Error("written file is not a valid docx", err)
+		return
+	}
+	written.Close()
+
+	// a failing write must keep the existing file intact
+	original, _ := os.ReadFile(target)
+	doc.SetPreWriteHook(func(fileName string, data []byte) ([]byte, error) {
+		return nil, fmt.Errorf("rejected")
+	})
+	if err := doc.WriteToFile(target); err == nil {
+		t.Error("expected writing to fail")
+	}
+	if current, _ := os.ReadFile(target); !bytes.Equal(current, original) {
+		t.Error("expected the existing file to be unchanged")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(target)); len(entries) != 1 {
+		t.Errorf("temporary file was not removed, want=%d, have=%d", 1, len(entries))
+	}
+}
+
+func TestDocument_WriteToFileBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "template.docx")
+	original := newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml(`<w:p><w:r><w:t>{key}</w:t></w:r></w:p>`)})
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the document itself is kept as backup
+	doc, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if err := doc.ReplaceAll(PlaceholderMap{"key": "value"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.WriteToFileBackup(path); err != nil {
+		t.Fatal("writing failed", err)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); !bytes.Equal(backup, original) {
+		t.Error("expected the backup to contain the original document")
+	}
+	written, err := Open(path)
+	if err != nil {
+		t.Fatal("written file is not a valid docx", err)
+	}
+	defer written.Close()
+	if text := writtenText(t, written, DocumentXml); text != "value" {
+		t.Errorf("unexpected text, want=%q, have=%q", "value", text)
+	}
+
+	// a failing write neither touches the file nor the backup
+	current, _ := os.ReadFile(path)
+	doc.SetPreWriteHook(func(fileName string, data []byte) ([]byte, error) {
+		return nil, fmt.Errorf("rejected")
+	})
+	if err := doc.WriteToFileBackup(path); err == nil {
+		t.Error("expected writing to fail")
+	}
+	if unchanged, _ := os.ReadFile(path); !bytes.Equal(unchanged, current) {
+		t.Error("expected the file to be unchanged")
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); !bytes.Equal(backup, original) {
+		t.Error("expected the backup to be unchanged")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("temporary file was not removed, want=%d, have=%d", 2, len(entries))
+	}
+
+	// new files do not have a backup
+	if err := written.WriteToFileBackup(filepath.Join(dir, "new.docx")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.docx.bak")); !os.IsNotExist(err) {
+		t.Error("expected no backup of a new file")
+	}
+}
+
+func TestDocument_EmptyDocument(t *testing.T) {
+	for name, body := range map[string]string{
+		"singleton body": "",
+		"empty body":     "<w:body></w:body>",
+	} {
+		t.Run(name, func(t *testing.T) {
+			documentXml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+				`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+				body + `</w:document>`
+			if body == "" {
+				documentXml = strings.Replace(documentXml, "</w:document>", "<w:body/></w:document>", 1)
+			}
+
+			doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: documentXml}))
+			if err != nil {
+				t.Fatal("opening an empty document must not fail", err)
+			}
+			if total, _ := doc.RunStats(DocumentXml); total != 0 {
+				t.Errorf("unexpected number of runs, want=%d, have=%d", 0, total)
+			}
+			if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err != nil {
+				t.Error("replacing in an empty document must be a no-op", err)
+			}
+			if err := doc.Replace("foo", "bar"); err != nil {
+				t.Error("replacing in an empty document must be a no-op", err)
+			}
+			if written := string(writtenFile(t, doc, DocumentXml)); written != documentXml {
+				t.Errorf("document was changed, have=%s", written)
+			}
+		})
+	}
+}
+
+func TestOpenBytes_DuplicateFile(t *testing.T) {
+	data := newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml("")})
+
+	// append a second document.xml to the otherwise valid archive
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range reader.File {
+		if err := zipWriter.Copy(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fw, err := zipWriter.Create(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte(newTestDocumentXml("<w:p><w:r><w:t>{evil}</w:t></w:r></w:p>"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = OpenBytes(buf.Bytes())
+	if !errors.Is(err, ErrDuplicateFile) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrDuplicateFile, err)
+	}
+}
+
+func TestDocument_SetUncompressed(t *testing.T) {
+	doc := openTestDocument(t, `<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`)
+	if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	methods := func() map[string]uint16 {
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		methods := make(map[string]uint16)
+		for _, file := range reader.File {
+			methods[file.Name] = file.Method
+		}
+		return methods
+	}
+
+	for name, method := range methods() {
+		if method != zip.Deflate {
+			t.Errorf("unexpected default method of %s, want=%d, have=%d", name, zip.Deflate, method)
+		}
+	}
+
+	doc.SetUncompressed(true)
+	for name, method := range methods() {
+		if method != zip.Store {
+			t.Errorf("unexpected method of %s, want=%d, have=%d", name, zip.Store, method)
+		}
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "bar" {
+		t.Errorf("unexpected text, want=bar, have=%s", text)
+	}
+}
+
+func TestDocument_SetPreserveUnchangedFiles(t *testing.T) {
+	// the escaped delimiters of the footer are only unescaped if the footer is written back
+	footer := `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:p><w:r><w:t>{{literal}}</w:t></w:r></w:p></w:ftr>`
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml:        newTestDocumentXml(`<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`),
+		"word/footer1.xml": footer,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if written := string(writtenFile(t, doc, "word/footer1.xml")); written == footer {
+		t.Error("expected the footer to be written back by default")
+	}
+
+	doc.SetPreserveUnchangedFiles(true)
+	if written := string(writtenFile(t, doc, "word/footer1.xml")); written != footer {
+		t.Errorf("unchanged footer was not preserved, want=%s, have=%s", footer, written)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "bar" {
+		t.Errorf("changed document was not written, have=%s", text)
+	}
+}
+
+func TestDocument_SetStripLastRenderedPageBreaks(t *testing.T) {
+	body := `<w:p><w:r><w:lastRenderedPageBreak/><w:t>{foo}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(writtenFile(t, doc, DocumentXml), []byte("<w:lastRenderedPageBreak/>")) {
+		t.Error("expected the hints to be kept by default")
+	}
+
+	doc.SetStripLastRenderedPageBreaks(true)
+	written := writtenFile(t, doc, DocumentXml)
+	if bytes.Contains(written, []byte("lastRenderedPageBreak")) {
+		t.Error("expected the hints to be removed")
+	}
+	if !bytes.Contains(written, []byte("<w:r><w:t>bar</w:t></w:r>")) {
+		t.Errorf("run was not kept, have=%s", written)
+	}
+}
+
+func TestDocument_IteratePlaceholders(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml: newTestDocumentXml(`<w:p><w:r><w:t>{a}{b}</w:t></w:r></w:p>`),
+		"word/header1.xml": `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:p><w:r><w:t>{c}</w:t></w:r></w:p></w:hdr>`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	doc.IteratePlaceholders(func(file string, p *Placeholder) bool {
+		visited = append(visited, file+":"+p.Text(doc.GetFile(file)))
+		return true
+	})
+	expected := []string{DocumentXml + ":{a}", DocumentXml + ":{b}", "word/header1.xml:{c}", "word/footer1.xml:{key}"}
+	if strings.Join(visited, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected placeholders, want=%v, have=%v", expected, visited)
+	}
+
+	calls := 0
+	doc.IteratePlaceholders(func(file string, p *Placeholder) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("iteration did not stop, want=%d, have=%d", 1, calls)
+	}
+}
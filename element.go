@@ -0,0 +1,82 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Element is the position of an XML element, see ElementPositions.
+type Element struct {
+	Name     xml.Name
+	OpenTag  Position
+	CloseTag Position
+}
+
+// SelfClosing returns true if the element is a singleton tag (e.g. <w:br/>), both of its tags are the same.
+func (e Element) SelfClosing() bool {
+	return e.OpenTag == e.CloseTag
+}
+
+// Content returns the bytes between the open and close tag of the element.
+func (e Element) Content(data []byte) []byte {
+	if e.SelfClosing() {
+		return nil
+	}
+	return data[e.OpenTag.End:e.CloseTag.Start]
+}
+
+// ElementPositions returns the byte positions of all elements with the given name inside the data, ordered by
+// their open tags. Nested elements are returned as well. This is the generalization of the RunParser, which allows
+// to build custom parsers for any part of the document, e.g. to locate all paragraphs (<w:p>).
+//
+// The Space of the name may either be the namespace URI or, if the namespace is not declared inside the data,
+// the prefix. An empty Space matches the local name in any namespace.
+func ElementPositions(data []byte, name xml.Name) ([]Element, error) {
+	reader := NewReader(string(data))
+	decoder := xml.NewDecoder(reader)
+
+	// tagPosition returns the position of the tag which was decoded last
+	tagPosition := func() Position {
+		end := reader.Pos()
+		return Position{Start: int64(bytes.LastIndexByte(data[:end], '<')), End: end}
+	}
+
+	var elements []Element
+	var open []int // indices of the elements whose close tag is not found yet
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting token: %s", err)
+		}
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			if !matchesName([]xml.Name{name}, elem.Name) {
+				break
+			}
+			position := tagPosition()
+			elements = append(elements, Element{Name: elem.Name, OpenTag: position})
+			open = append(open, len(elements)-1)
+
+		case xml.EndElement:
+			if !matchesName([]xml.Name{name}, elem.Name) || len(open) == 0 {
+				break
+			}
+			element := &elements[open[len(open)-1]]
+			open = open[:len(open)-1]
+
+			// the decoder returns an EndElement for singleton tags as well, right after the StartElement
+			if bytes.HasSuffix(data[element.OpenTag.Start:element.OpenTag.End], []byte("/>")) {
+				element.CloseTag = element.OpenTag
+				break
+			}
+			element.CloseTag = tagPosition()
+		}
+	}
+	return elements, nil
+}
@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestElementPositions(t *testing.T) {
+	data := []byte(`<w:body xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:p w:rsidR="00A1"><w:r><w:t>first</w:t></w:r></w:p><w:p/>` +
+		`<w:p><w:r><w:txbxContent><w:p>nested</w:p></w:txbxContent></w:r></w:p></w:body>`)
+
+	tests := []struct {
+		name     xml.Name
+		expected []string // open tag and content of every element
+	}{
+		{
+			name:     xml.Name{Local: "p"},
+			expected: []string{`<w:p w:rsidR="00A1">`, `<w:r><w:t>first</w:t></w:r>`, `<w:p/>`, ``, `<w:p>`, `<w:r><w:txbxContent><w:p>nested</w:p></w:txbxContent></w:r>`, `<w:p>`, `nested`},
+		},
+		{
+			name:     xml.Name{Space: "http://schemas.openxmlformats.org/wordprocessingml/2006/main", Local: "t"},
+			expected: []string{`<w:t>`, `first`},
+		},
+		{
+			name: xml.Name{Space: "http://schemas.openxmlformats.org/drawingml/2006/main", Local: "t"},
+		},
+	}
+	for _, tt := range tests {
+		elements, err := ElementPositions(data, tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have []string
+		for _, element := range elements {
+			have = append(have, string(data[element.OpenTag.Start:element.OpenTag.End]), string(element.Content(data)))
+			if !element.SelfClosing() && string(data[element.CloseTag.Start:element.CloseTag.End]) != "</w:"+tt.name.Local+">" {
+				t.Errorf("invalid close tag of %s at %v", tt.name.Local, element.CloseTag)
+			}
+		}
+		if len(have) != len(tt.expected) {
+			t.Errorf("unexpected text of %s, want=%q, have=%q", tt.name.Local, tt.expected, have)
+			continue
+		}
+		for i := range have {
+			if have[i] != tt.expected[i] {
+				t.Errorf("unexpected text of %s, want=%q, have=%q", tt.name.Local, tt.expected, have)
+				break
+			}
+		}
+	}
+
+	if _, err := ElementPositions([]byte(`<w:p><w:r></w:p>`), xml.Name{Local: "p"}); err == nil {
+		t.Error("expected an error for malformed xml")
+	}
+}
@@ -0,0 +1,62 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// TextRunRegex matches a complete text-run (<w:t>...</w:t>), the submatches are the open tag, the text and the close tag.
+	TextRunRegex = regexp.MustCompile(`(<w:t(?:\s[^>]*)?>)([^<]*)(</w:t>)`)
+)
+
+// SetEscapeDelimiters enables or disables escaping delimiters by doubling them (e.g. '{{').
+// If enabled, the escaped delimiters of the template are written as single delimiters on Write() and the delimiters
+// inside the values are escaped, thus they always end up as literals in the document.
+// It is disabled by default, the text of the template is written as it is and values are inserted unchanged.
+// Escaped delimiters are never parsed as placeholders, independent of this setting.
+func (d *Document) SetEscapeDelimiters(escape bool) {
+	d.escapeDelimiters = escape
+}
+
+// escapeValue escapes the delimiters inside the value if escaping is enabled.
+func (d *Document) escapeValue(value string) string {
+	if !d.escapeDelimiters {
+		return value
+	}
+	return d.delimiters.escape(value)
+}
+
+// EscapeDelimiters escapes all delimiters in the given text by doubling them.
+// Escaped delimiters are not treated as placeholder delimiters but end up as literals in the written document.
+//
+// Example: '{foo}' becomes '{{foo}}' which will be written as '{foo}'.
+func EscapeDelimiters(s string) string {
+	return defaultDelimiters.escape(s)
+}
+
+// UnescapeDelimiters turns all escaped (doubled) delimiters back into single delimiters.
+func UnescapeDelimiters(s string) string {
+	return defaultDelimiters.unescape(s)
+}
+
+// escape doubles the delimiters inside the text, see EscapeDelimiters.
+func (delims delimiters) escape(s string) string {
+	s = strings.Replace(s, string(delims.open), strings.Repeat(string(delims.open), 2), -1)
+	return strings.Replace(s, string(delims.close), strings.Repeat(string(delims.close), 2), -1)
+}
+
+// unescape turns the doubled delimiters inside the text back into single ones, see UnescapeDelimiters.
+func (delims delimiters) unescape(s string) string {
+	s = strings.Replace(s, strings.Repeat(string(delims.open), 2), string(delims.open), -1)
+	return strings.Replace(s, strings.Repeat(string(delims.close), 2), string(delims.close), -1)
+}
+
+// unescapeTextRuns will unescape the delimiters inside all text-runs of the given file.
+// The XML structure itself is never touched.
+func unescapeTextRuns(data []byte, delims delimiters) []byte {
+	return TextRunRegex.ReplaceAllFunc(data, func(textRun []byte) []byte {
+		parts := TextRunRegex.FindSubmatch(textRun)
+		return []byte(string(parts[1]) + delims.unescape(string(parts[2])) + string(parts[3]))
+	})
+}
@@ -0,0 +1,74 @@
+package docx
+
+import "testing"
+
+func TestDocument_EscapedDelimiters(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">{{literal}} {key} {{{key}}} {{</w:t></w:r></w:p>`
+	tests := []struct {
+		escape   bool
+		expected string
+	}{
+		// by default the template and the values are written as they are
+		{escape: false, expected: "{{literal}} {value} {{{value}}} {{"},
+		{escape: true, expected: "{literal} {value} {{value}} {"},
+	}
+
+	for _, tt := range tests {
+		doc := openTestDocument(t, body)
+		doc.SetEscapeDelimiters(tt.escape)
+
+		placeholders := doc.filePlaceholders[DocumentXml]
+		if len(placeholders) != 2 {
+			t.Errorf("escaped delimiters must not be parsed as placeholder, want=%d, have=%d", 2, len(placeholders))
+			return
+		}
+
+		if err := doc.ReplaceAll(PlaceholderMap{"key": "{value}"}); err != nil {
+			t.Error("replacing failed", err)
+			return
+		}
+
+		if text := writtenText(t, doc, DocumentXml); text != tt.expected {
+			t.Errorf("unexpected text with escape=%t, want=%s, have=%s", tt.escape, tt.expected, text)
+		}
+	}
+}
+
+func TestDelimiterPositions(t *testing.T) {
+	tests := []struct {
+		text          string
+		inPlaceholder bool
+		openPos       []int
+		closePos      []int
+	}{
+		{text: "{foo}{bar}", openPos: []int{0, 5}, closePos: []int{4, 9}},
+		{text: "{{foo}}"},
+		{text: "{{{foo}}}", openPos: []int{2}, closePos: []int{6}},
+		{text: "foo}}}", inPlaceholder: true, closePos: []int{3}},
+		{text: "}foo{", openPos: []int{4}, closePos: []int{0}},
+		{text: "{foo}\u0301"},
+		{text: "{a}\u0301 and {b} end", openPos: []int{10}, closePos: []int{12}},
+		{text: "{\u200dfoo}", closePos: []int{7}},
+		{text: "{}{foo}", openPos: []int{2}, closePos: []int{6}},
+		{text: "{foo{}}", openPos: []int{0}, closePos: []int{6}},
+	}
+
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, tt := range tests {
+		openPos, closePos := delimiterPositions(tt.text, tt.inPlaceholder, defaultDelimiters)
+		if !equal(openPos, tt.openPos) || !equal(closePos, tt.closePos) {
+			t.Errorf("unexpected positions for '%s', want=%v %v, have=%v %v", tt.text, tt.openPos, tt.closePos, openPos, closePos)
+		}
+	}
+}
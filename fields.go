@@ -0,0 +1,35 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var (
+	// FieldStartRegex matches the elements which start a field: the begin character of a complex field
+	// (<w:fldChar w:fldCharType="begin"/>) and simple fields (<w:fldSimple>).
+	FieldStartRegex = regexp.MustCompile(`<w:fldChar\b[^>]*\bw:fldCharType="begin"[^>]*>|<w:fldSimple\b[^>]*>`)
+	// fieldDirtyAttrRegex matches an existing dirty attribute of a field.
+	fieldDirtyAttrRegex = regexp.MustCompile(`\sw:dirty="[^"]*"`)
+)
+
+// MarkFieldsDirty marks all fields of the document, headers and footers as dirty (w:dirty="true") on Write().
+// Word updates dirty fields when the document is opened. Fields cache their result, e.g. a table of contents
+// lists the headings as they were when the template was saved. Thus replacing placeholders inside of headings
+// would not be reflected by the table of contents until it is updated.
+// Depending on its settings, Word asks the user before updating the fields.
+func (d *Document) MarkFieldsDirty() {
+	d.markFieldsDirty = true
+}
+
+// markFieldsDirty sets the dirty attribute of all fields inside the data.
+func markFieldsDirty(data []byte) []byte {
+	return FieldStartRegex.ReplaceAllFunc(data, func(tag []byte) []byte {
+		tag = fieldDirtyAttrRegex.ReplaceAll(tag, nil)
+		// the attribute is added behind the element name, e.g. '<w:fldSimple'
+		name := bytes.IndexAny(tag, " \t\r\n/>")
+		marked := append([]byte(nil), tag[:name]...)
+		marked = append(marked, ` w:dirty="true"`...)
+		return append(marked, tag[name:]...)
+	})
+}
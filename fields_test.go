@@ -0,0 +1,37 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDocument_MarkFieldsDirty(t *testing.T) {
+	body := `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> TOC \o "1-3" </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>{heading}</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:fldSimple w:instr=" PAGE " w:dirty="false"><w:r><w:t>1</w:t></w:r></w:fldSimple></w:p>`
+	doc := openTestDocument(t, body)
+	if err := doc.ReplaceAll(PlaceholderMap{"heading": "Introduction"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// fields are only marked if enabled
+	if data := writtenFile(t, doc, DocumentXml); bytes.Contains(data, []byte(`w:dirty="true"`)) {
+		t.Errorf("fields were not kept, have=%s", data)
+	}
+
+	doc.MarkFieldsDirty()
+	data := writtenFile(t, doc, DocumentXml)
+	for _, tag := range []string{
+		`<w:fldChar w:dirty="true" w:fldCharType="begin"/>`,
+		`<w:fldSimple w:dirty="true" w:instr=" PAGE ">`,
+		`<w:fldChar w:fldCharType="separate"/>`,
+		`<w:fldChar w:fldCharType="end"/>`,
+	} {
+		if !bytes.Contains(data, []byte(tag)) {
+			t.Errorf("missing %s, have=%s", tag, data)
+		}
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "Introduction1" {
+		t.Errorf("unexpected text, want=%s, have=%s", "Introduction1", text)
+	}
+}
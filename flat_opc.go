@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FlatOPCNamespace is the namespace of the Flat OPC package format.
+	FlatOPCNamespace = "http://schemas.microsoft.com/office/2006/xmlPackage"
+	// flatOPCLineLength is the length of the lines of base64 encoded binary parts.
+	flatOPCLineLength = 76
+)
+
+// xmlDeclarationRegex matches the XML declaration at the start of a part.
+var xmlDeclarationRegex = regexp.MustCompile(`^\s*<\?xml[^>]*\?>\s*`)
+
+// WriteFlatOPC writes the document as Flat OPC package, a single XML file which contains all parts of the archive.
+// Word is able to open it directly, which makes it a useful format to inspect or diff the generated document.
+// The parts have the same content as they would have using Write. XML parts are embedded as they are while all other
+// parts (e.g. images) are embedded base64 encoded.
+func (d *Document) WriteFlatOPC(writer io.Writer) error {
+	var archive bytes.Buffer
+	if err := d.Write(&archive); err != nil {
+		return err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		return fmt.Errorf("unable to read written archive: %s", err)
+	}
+
+	w := bufio.NewWriter(writer)
+	w.WriteString(`<?xml version="1.0" standalone="yes"?>` + "\n")
+	w.WriteString(`<?mso-application progid="Word.Document"?>` + "\n")
+	w.WriteString(`<pkg:package xmlns:pkg="` + FlatOPCNamespace + `">` + "\n")
+
+	for _, zipFile := range reader.File {
+		// the content types are stored with every part instead
+		if zipFile.Name == ContentTypesXml || strings.HasSuffix(zipFile.Name, "/") {
+			continue
+		}
+		if err := d.writeFlatOPCPart(w, zipFile); err != nil {
+			return err
+		}
+	}
+
+	w.WriteString(`</pkg:package>` + "\n")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("unable to write flat OPC package: %s", err)
+	}
+	return nil
+}
+
+// writeFlatOPCPart writes a single <pkg:part> of the Flat OPC package.
+func (d *Document) writeFlatOPCPart(w *bufio.Writer, zipFile *zip.File) error {
+	readCloser, err := zipFile.Open()
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %s", zipFile.Name, err)
+	}
+	data, err := ioutil.ReadAll(readCloser)
+	readCloser.Close()
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %s", zipFile.Name, err)
+	}
+
+	contentType, err := d.contentType(zipFile.Name)
+	if err != nil {
+		return err
+	}
+	if contentType == "" && strings.HasSuffix(zipFile.Name, ".rels") {
+		contentType = RelationshipsContentType
+	}
+
+	fmt.Fprintf(w, `<pkg:part pkg:name="/%s" pkg:contentType="%s"`, html.EscapeString(zipFile.Name), html.EscapeString(contentType))
+	if content := xmlDeclarationRegex.ReplaceAll(data, nil); isXmlPart(zipFile.Name) && len(bytes.TrimSpace(content)) > 0 {
+		w.WriteString(`><pkg:xmlData>`)
+		w.Write(bytes.TrimSpace(content))
+		w.WriteString("</pkg:xmlData></pkg:part>\n")
+		return nil
+	}
+
+	w.WriteString(` pkg:compression="store"><pkg:binaryData>`)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > flatOPCLineLength {
+		w.WriteString(encoded[:flatOPCLineLength] + "\n")
+		encoded = encoded[flatOPCLineLength:]
+	}
+	w.WriteString(encoded)
+	w.WriteString("</pkg:binaryData></pkg:part>\n")
+	return nil
+}
@@ -0,0 +1,72 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteFlatOPC(t *testing.T) {
+	doc, err := OpenBytes(newTestImageDocx(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteFlatOPC(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWellFormed(buf.Bytes()); err != nil {
+		t.Fatalf("package is not well-formed, have=%s\n%s", err, buf.String())
+	}
+
+	var pkg struct {
+		Parts []struct {
+			Name        string `xml:"name,attr"`
+			ContentType string `xml:"contentType,attr"`
+			XmlData     struct {
+				Inner []byte `xml:",innerxml"`
+			} `xml:"xmlData"`
+			BinaryData string `xml:"binaryData"`
+		} `xml:"part"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	parts := make(map[string]int)
+	for i, part := range pkg.Parts {
+		parts[part.Name] = i
+	}
+	if _, exists := parts["/"+ContentTypesXml]; exists {
+		t.Error("expected the content types not to be a part")
+	}
+
+	document, exists := parts["/"+DocumentXml]
+	if !exists {
+		t.Fatalf("document is not a part, have=%v", parts)
+	}
+	if !bytes.Equal(pkg.Parts[document].XmlData.Inner, xmlDeclarationRegex.ReplaceAll(writtenFile(t, doc, DocumentXml), nil)) {
+		t.Errorf("document is not written as xml data, have=%s", pkg.Parts[document].XmlData.Inner)
+	}
+	if !strings.HasSuffix(pkg.Parts[document].ContentType, "document.main+xml") {
+		t.Errorf("unexpected content type of the document, have=%s", pkg.Parts[document].ContentType)
+	}
+
+	images := doc.Images()
+	if len(images) == 0 {
+		t.Fatal("expected the test document to contain images")
+	}
+	for _, image := range images {
+		index, exists := parts["/"+image.Name]
+		if !exists {
+			t.Fatalf("expected the image %s to be a part", image.Name)
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(pkg.Parts[index].BinaryData, "\n", ""))
+		if err != nil || !bytes.Equal(data, image.Data) {
+			t.Errorf("image data is not encoded, have=%v", err)
+		}
+	}
+}
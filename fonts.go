@@ -0,0 +1,60 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+)
+
+const (
+	// FontTableXml is the file which declares all fonts used inside the document.
+	FontTableXml = "word/fontTable.xml"
+)
+
+// fontTableXml is the root element of the FontTableXml.
+type fontTableXml struct {
+	Fonts []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"font"`
+}
+
+// Fonts returns the names of all fonts declared in the font table of the document.
+func (d *Document) Fonts() ([]string, error) {
+	data, err := d.readRawFile(FontTableXml)
+	if err != nil {
+		return nil, err
+	}
+	var table fontTableXml
+	if err := xml.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", FontTableXml, err)
+	}
+
+	var fonts []string
+	for _, font := range table.Fonts {
+		fonts = append(fonts, font.Name)
+	}
+	return fonts, nil
+}
+
+// EnsureFont declares the font with the given name in the font table, if it is not declared yet.
+// This is required in order for Word to render text which uses the font properly.
+// Only the declaration is added, the font itself is not embedded into the document.
+func (d *Document) EnsureFont(name string) error {
+	fonts, err := d.Fonts()
+	if err != nil {
+		return err
+	}
+	for _, font := range fonts {
+		if font == name {
+			return nil
+		}
+	}
+
+	data, err := d.readRawFile(FontTableXml)
+	if err != nil {
+		return err
+	}
+	font := fmt.Sprintf(`<w:font w:name="%s"><w:family w:val="auto"/><w:pitch w:val="default"/></w:font>`, html.EscapeString(name))
+	d.setRawFile(FontTableXml, insertBeforeClosingTag(data, "</w:fonts>", font))
+	return nil
+}
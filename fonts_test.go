@@ -0,0 +1,35 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_EnsureFont(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, font := range []string{"Arial", "Fira Sans", "Fira Sans"} {
+		if err := doc.EnsureFont(font); err != nil {
+			t.Errorf("ensuring font %s failed: %s", font, err)
+			return
+		}
+	}
+
+	fonts, err := doc.Fonts()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if n := len(fonts); n != 8 {
+		t.Errorf("unexpected amount of fonts, want=%d, have=%d: %v", 8, n, fonts)
+	}
+
+	fontTable := string(writtenFile(t, doc, FontTableXml))
+	if n := strings.Count(fontTable, `<w:font w:name="Fira Sans">`); n != 1 {
+		t.Errorf("unexpected number of font declarations, want=%d, have=%d", 1, n)
+	}
+}
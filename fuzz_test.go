@@ -0,0 +1,68 @@
+package docx
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addFuzzSeeds adds all XML fixtures and some small documents to the seed corpus.
+// The diagnostic output is discarded since it would flood the output of the fuzzing workers.
+func addFuzzSeeds(f *testing.F) {
+	SetLogger(nil)
+	f.Cleanup(func() { SetLogger(log.New(os.Stderr, "", log.LstdFlags)) })
+
+	files, err := filepath.Glob("./test/*.xml")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`))
+	f.Add([]byte(`<w:p><w:r><w:t>{fo</w:t></w:r><w:r><w:t>o} {bar}</w:t></w:r></w:p>`))
+	f.Add([]byte(`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">{</w:t></w:r><w:r/><w:r><w:t>}</w:t></w:r>`))
+}
+
+func FuzzRunParser(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parser := NewRunParser(data)
+		if err := parser.Execute(); err != nil {
+			return
+		}
+		if err := ValidatePositions(data, parser.Runs()); err != nil {
+			t.Fatalf("parsed runs have invalid positions: %s", err)
+		}
+		for _, run := range parser.Runs() {
+			_ = run.GetText(data)
+			_ = run.String(data)
+		}
+	})
+}
+
+func FuzzParsePlaceholders(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parser := NewRunParser(data)
+		if err := parser.Execute(); err != nil {
+			return
+		}
+		placeholders, err := ParsePlaceholders(parser.Runs(), data)
+		if err != nil {
+			return
+		}
+		for _, placeholder := range placeholders {
+			_ = placeholder.Text(data)
+		}
+
+		// replacing the parsed placeholders must never panic either
+		replacer := NewReplacer(copyBytes(data), placeholders)
+		_, _ = replacer.ReplaceRemaining("value")
+	})
+}
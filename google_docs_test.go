@@ -0,0 +1,66 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The fixture is hand-written after the structure of Google Docs exports, it is not an actual export: every run
+// carries its own properties (at least <w:rtl w:val="0"/>), placeholders are split wherever the formatting changed,
+// bookmarks are placed between runs and paragraphs end with empty runs.
+// TODO: replace it by the document.xml of a real Google Docs export.
+func TestGoogleDocsLikeStructure(t *testing.T) {
+	open := func(t *testing.T) *Document {
+		doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/google_docs_like.xml"))}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+	placeholderMap := PlaceholderMap{
+		"invoice_number": "2023-042",
+		"first_name":     "Jane",
+		"order_date":     "March 3rd",
+		"payment_link":   "https://example.com/pay",
+		"item":           "Consulting",
+		"amount":         "1.200,00 €",
+	}
+	expectedText := "Invoice 2023-042" + "Dear Jane, thank you for your order from March 3rd." +
+		"https://example.com/pay" + "Consulting" + "1.200,00 €"
+
+	doc := open(t)
+	var texts []string
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		texts = append(texts, placeholder.Text(doc.files[DocumentXml]))
+	}
+	expected := []string{"{invoice_number}", "{first_name}", "{order_date}", "{payment_link}", "{item}", "{amount}"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%q, have=%q", expected, texts)
+	}
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != expectedText {
+		t.Errorf("unexpected text, want=%q, have=%q", expectedText, text)
+	}
+
+	// runs with the same properties are merged, even though Google Docs adds the revision ids to every run
+	doc = open(t)
+	if err := doc.MergeRuns(); err != nil {
+		t.Fatal(err)
+	}
+	// differently formatted fragments and the ones around a bookmark are kept, the bookmark would move otherwise
+	var fragments []int
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		fragments = append(fragments, len(placeholder.Fragments))
+	}
+	if expected := []int{1, 2, 2, 1, 1, 1}; !reflect.DeepEqual(fragments, expected) {
+		t.Errorf("unexpected fragments per placeholder, want=%v, have=%v", expected, fragments)
+	}
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != expectedText {
+		t.Errorf("unexpected text after merging runs, want=%q, have=%q", expectedText, text)
+	}
+}
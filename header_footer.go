@@ -0,0 +1,82 @@
+package docx
+
+import (
+	"regexp"
+)
+
+// HeaderType is the type of a header or footer reference of a section.
+type HeaderType string
+
+const (
+	// HeaderDefault is used for all pages of a section which do not use another header or footer.
+	HeaderDefault HeaderType = "default"
+	// HeaderEven is used for even pages if the document has different headers for odd and even pages.
+	HeaderEven HeaderType = "even"
+	// HeaderFirst is used for the first page of a section if it has a different first page.
+	HeaderFirst HeaderType = "first"
+)
+
+// headerReferenceRegex matches the header and footer references of the section properties.
+var headerReferenceRegex = regexp.MustCompile(`<w:(header|footer)Reference\s[^>]*>`)
+
+// HeaderByType returns the header of the given type, resolved through the section properties of the document.
+// If the document has multiple sections, the header of the first section which references one is returned.
+// The returned bytes are the current content of the header, including all replacements.
+func (d *Document) HeaderByType(headerType HeaderType) ([]byte, bool) {
+	return d.referencedFile("header", headerType)
+}
+
+// FooterByType returns the footer of the given type, see HeaderByType.
+func (d *Document) FooterByType(headerType HeaderType) ([]byte, bool) {
+	return d.referencedFile("footer", headerType)
+}
+
+// HeaderFileByType returns the name of the header file of the given type inside the archive, see HeaderByType.
+// The name can be used to replace the placeholders only inside that header, e.g. using GetFile and SetFile.
+func (d *Document) HeaderFileByType(headerType HeaderType) (string, bool) {
+	return d.referencedFileName("header", headerType)
+}
+
+// FooterFileByType returns the name of the footer file of the given type inside the archive, see HeaderByType.
+func (d *Document) FooterFileByType(headerType HeaderType) (string, bool) {
+	return d.referencedFileName("footer", headerType)
+}
+
+// referencedFile returns the content of the header or footer of the given type.
+func (d *Document) referencedFile(kind string, headerType HeaderType) ([]byte, bool) {
+	name, found := d.referencedFileName(kind, headerType)
+	if !found {
+		return nil, false
+	}
+	data, err := d.readRawFile(name)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// referencedFileName resolves the first reference of the given kind (header or footer) and type
+// to the name of the referenced file.
+func (d *Document) referencedFileName(kind string, headerType HeaderType) (string, bool) {
+	for _, match := range headerReferenceRegex.FindAllSubmatch(d.files[DocumentXml], -1) {
+		if string(match[1]) != kind {
+			continue
+		}
+		attrs := tagAttributes(match[0])
+		// the type is optional, a missing type is treated as default
+		referenceType := HeaderType(attrs["w:type"])
+		if referenceType == "" {
+			referenceType = HeaderDefault
+		}
+		if referenceType != headerType {
+			continue
+		}
+		rel, err := d.relationship(DocumentXml, attrs["r:id"])
+		if err != nil {
+			logger.Printf("unable to resolve %s reference: %s", kind, err)
+			continue
+		}
+		return relationshipTargetPath(DocumentXml, rel.Target), true
+	}
+	return "", false
+}
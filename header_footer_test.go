@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDocument_HeaderByType(t *testing.T) {
+	header := func(text string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:hdr>`
+	}
+	rels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="` + HeaderRelationshipType + `" Target="header1.xml"/>` +
+		`<Relationship Id="rId2" Type="` + HeaderRelationshipType + `" Target="header2.xml"/>` +
+		`<Relationship Id="rId3" Type="` + HeaderRelationshipType + `" Target="/word/header3.xml"/>` +
+		`</Relationships>`
+	body := `<w:p><w:r><w:t>Cover</w:t></w:r></w:p><w:sectPr>` +
+		`<w:headerReference w:type="first" r:id="rId2"/><w:headerReference r:id="rId1"/>` +
+		`<w:titlePg/></w:sectPr>`
+
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml:                    newTestDocumentXml(body),
+		"word/_rels/document.xml.rels": rels,
+		"word/header1.xml":             header("Default"),
+		"word/header2.xml":             header("First {page}"),
+		"word/header3.xml":             header("Unreferenced"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, found := doc.HeaderByType(HeaderFirst)
+	if !found || !bytes.Contains(first, []byte("First {page}")) {
+		t.Errorf("unexpected first page header, have=%s", first)
+	}
+	if name, _ := doc.HeaderFileByType(HeaderFirst); name != "word/header2.xml" {
+		t.Errorf("unexpected header, want=%s, have=%s", "word/header2.xml", name)
+	}
+	if defaultHeader, found := doc.HeaderByType(HeaderDefault); !found || !bytes.Contains(defaultHeader, []byte("Default")) {
+		t.Errorf("reference without type is not the default header, have=%s", defaultHeader)
+	}
+	if _, found := doc.HeaderByType(HeaderEven); found {
+		t.Error("expected no even header")
+	}
+	if _, found := doc.FooterByType(HeaderDefault); found {
+		t.Error("expected no footer")
+	}
+
+	// the returned header reflects replacements
+	if err := doc.ReplaceAll(PlaceholderMap{"page": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if first, _ := doc.HeaderByType(HeaderFirst); !bytes.Contains(first, []byte("First 1")) {
+		t.Errorf("header was not replaced, have=%s", first)
+	}
+}
@@ -0,0 +1,263 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ImageRelationshipType is the type of the relationship from a part to an embedded image.
+	ImageRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+
+	// EmusPerPoint is the number of English Metric Units (EMU) per point, drawings are sized in EMU.
+	EmusPerPoint = 12700
+
+	wordprocessingDrawingNamespace = "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+	relationshipsNamespace         = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+var (
+	// ErrImageNotFound is returned if no image with the requested alt-text or title exists inside the document.
+	ErrImageNotFound = errors.New("image not found in document")
+
+	// DrawingPropertiesRegex matches the non-visual properties (<wp:docPr>) of a drawing which hold its alt-text and title.
+	DrawingPropertiesRegex = regexp.MustCompile(`<wp:docPr\s[^>]*>`)
+	// blipEmbedRegex matches the reference to the embedded image of a drawing.
+	blipEmbedRegex = regexp.MustCompile(`<a:blip\s[^>]*r:embed="([^"]*)"`)
+	// attributeRegex matches a single attribute of a tag, capturing its name and value.
+	attributeRegex = regexp.MustCompile(`([\w:]+)="([^"]*)"`)
+	// drawingIDRegex matches the id of the non-visual properties of a drawing.
+	drawingIDRegex = regexp.MustCompile(`<wp:docPr\s[^>]*?\bid="(\d+)"`)
+)
+
+// ImageData holds the binary data of an image.
+type ImageData struct {
+	// Data is the encoded image (e.g. the content of a png file).
+	Data []byte
+	// ContentType is the MIME type of the image (e.g. 'image/png').
+	// If empty, the content type is detected from the data.
+	ContentType string
+}
+
+// contentType returns the content type of the image, detecting it if it is not set.
+func (i ImageData) contentType() string {
+	if i.ContentType != "" {
+		return i.ContentType
+	}
+	return http.DetectContentType(i.Data)
+}
+
+// ImagePart is a media part of the document.
+type ImagePart struct {
+	// Name is the name of the part inside the archive, e.g. 'word/media/image1.png'.
+	Name string
+	// ContentType is the declared content type of the part, or the detected one if none is declared.
+	ContentType string
+	// Data is the current content of the part, including replacements.
+	Data []byte
+}
+
+// Images returns all media parts inside 'word/media/', sorted by their name.
+// The parts are read from the archive on every call. Parts which cannot be read are skipped.
+func (d *Document) Images() []ImagePart {
+	var names []string
+	for _, name := range d.partNames() {
+		if strings.HasPrefix(name, "word/media/") && !strings.HasSuffix(name, "/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var images []ImagePart
+	for _, name := range names {
+		data, err := d.readRawFile(name)
+		if err != nil {
+			logger.Printf("unable to read image %s: %s", name, err)
+			continue
+		}
+		contentType, err := d.contentType(name)
+		if err != nil || contentType == "" {
+			contentType = ImageData{Data: data}.contentType()
+		}
+		images = append(images, ImagePart{Name: name, ContentType: contentType, Data: data})
+	}
+	return images
+}
+
+// ReplaceExistingImage replaces the binary data of all images whose description (alt-text) or title matches
+// the given altText. Size, position and all other properties of the images are kept.
+// If the format of the image changes, the content type of the image part is updated.
+//
+// If no image with the given alt-text or title exists, ErrImageNotFound is returned.
+func (d *Document) ReplaceExistingImage(altText string, newImage ImageData) error {
+	contentType := newImage.contentType()
+	if !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("unsupported image content type %s", contentType)
+	}
+
+	media, err := d.findImages(altText)
+	if err != nil {
+		return err
+	}
+	if len(media) == 0 {
+		return fmt.Errorf("%w: %s", ErrImageNotFound, altText)
+	}
+
+	for _, part := range media {
+		if _, err := d.readRawFile(part); err != nil {
+			return fmt.Errorf("unable to replace image: %w", err)
+		}
+		if err := d.addFile(part, newImage.Data, contentType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findImages returns the media parts of all drawings whose description or title matches the altText.
+func (d *Document) findImages(altText string) ([]string, error) {
+	var media []string
+	for _, file := range d.parsedFiles() {
+		data := d.GetFile(file)
+		for _, loc := range DrawingPropertiesRegex.FindAllIndex(data, -1) {
+			attrs := tagAttributes(data[loc[0]:loc[1]])
+			if attrs["descr"] != altText && attrs["title"] != altText {
+				continue
+			}
+
+			// the image is referenced by the blip which follows the properties inside the same drawing
+			drawing := data[loc[1]:]
+			if end := bytes.Index(drawing, []byte("</w:drawing>")); end >= 0 {
+				drawing = drawing[:end]
+			}
+			blip := blipEmbedRegex.FindSubmatch(drawing)
+			if blip == nil {
+				continue
+			}
+
+			rel, err := d.relationship(file, string(blip[1]))
+			if err != nil {
+				return nil, err
+			}
+			if rel.TargetMode == TargetModeExternal {
+				return nil, fmt.Errorf("image %s is linked externally and cannot be replaced", altText)
+			}
+			media = append(media, relationshipTargetPath(file, rel.Target))
+		}
+	}
+	return media, nil
+}
+
+// tagAttributes returns the unescaped values of all attributes of the given tag by their qualified name.
+func tagAttributes(tag []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range attributeRegex.FindAllSubmatch(tag, -1) {
+		attrs[string(attr[1])] = html.UnescapeString(string(attr[2]))
+	}
+	return attrs
+}
+
+// replaceWithImage replaces all placeholders with the given key by an inline image of the given size in EMU.
+// The image is added as a new media part, which is referenced by every part containing the placeholder.
+// All modified files are parsed again afterwards.
+//
+// If no placeholder with the given key exists, ErrPlaceholderNotFound is returned.
+func (d *Document) replaceWithImage(key string, image ImageData, width, height int64, altText string) error {
+	contentType := image.contentType()
+	if !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("unsupported image content type %s", contentType)
+	}
+
+	files, err := d.markPlaceholders(key)
+	if err != nil {
+		return err
+	}
+
+	media := d.newMediaPartName(contentType)
+	if err := d.addFile(media, image.Data, contentType); err != nil {
+		return err
+	}
+	drawingID := d.maxDrawingID()
+	for i, file := range files {
+		id, err := d.AddRelationship(file.name, ImageRelationshipType, relationshipTarget(file.name, media), false)
+		if err != nil {
+			return err
+		}
+
+		// the drawing is placed between the text before and after the placeholder inside the same run
+		data := replaceMarkers(file.data, func(data []byte, pos int) string {
+			drawingID++
+			return `</w:t>` + inlineDrawing(id, drawingID, width, height, altText, path.Base(media)) + `<w:t xml:space="preserve">`
+		})
+		data = declareNamespace(data, rootElementName(data), "wp", wordprocessingDrawingNamespace)
+		files[i].data = declareNamespace(data, rootElementName(data), "r", relationshipsNamespace)
+	}
+
+	// the files are only modified once all of them were prepared
+	for _, file := range files {
+		d.files[file.name] = file.data
+		if err := d.parseFile(file.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newMediaPartName returns the name of a media part which does not exist yet, e.g. 'word/media/image3.png'.
+func (d *Document) newMediaPartName(contentType string) string {
+	ext := strings.TrimPrefix(contentType, "image/")
+	if i := strings.IndexAny(ext, "+;"); i >= 0 {
+		ext = ext[:i]
+	}
+	if ext == "jpeg" {
+		ext = "jpg"
+	}
+
+	existing := make(map[string]bool)
+	for _, name := range d.partNames() {
+		existing[name] = true
+	}
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("word/media/image%d.%s", n, ext)
+		if !existing[name] {
+			return name
+		}
+	}
+}
+
+// maxDrawingID returns the highest id of all drawings of the parsed files.
+// The ids must be unique inside the document.
+func (d *Document) maxDrawingID() int {
+	max := 0
+	for _, name := range d.allParsedFiles() {
+		for _, match := range drawingIDRegex.FindAllSubmatch(d.files[name], -1) {
+			if id, err := strconv.Atoi(string(match[1])); err == nil && id > max {
+				max = id
+			}
+		}
+	}
+	return max
+}
+
+// inlineDrawing returns the markup of an image which is placed inline with the text.
+func inlineDrawing(relID string, id int, width, height int64, altText, name string) string {
+	return fmt.Sprintf(`<w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">`+
+		`<wp:extent cx="%[3]d" cy="%[4]d"/><wp:docPr id="%[2]d" name="Picture %[2]d" descr="%[5]s"/>`+
+		`<wp:cNvGraphicFramePr><a:graphicFrameLocks xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" noChangeAspect="1"/></wp:cNvGraphicFramePr>`+
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">`+
+		`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:nvPicPr><pic:cNvPr id="%[2]d" name="%[6]s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip r:embed="%[1]s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[3]d" cy="%[4]d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`+
+		`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing>`,
+		relID, id, width, height, html.EscapeString(altText), html.EscapeString(name))
+}
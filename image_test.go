@@ -0,0 +1,100 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newTestImageDocx returns a docx with two images, 'logo' is referenced by its alt-text and 'banner' by its title.
+func newTestImageDocx(t testing.TB) []byte {
+	drawing := func(id, properties string) string {
+		return `<w:p><w:r><w:drawing><wp:inline>` +
+			`<wp:extent cx="952500" cy="952500"/>` +
+			`<wp:docPr id="1" name="Picture 1" ` + properties + `/>` +
+			`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:pic><pic:blipFill>` +
+			`<a:blip r:embed="` + id + `"/>` +
+			`</pic:blipFill></pic:pic></a:graphicData></a:graphic>` +
+			`</wp:inline></w:drawing></w:r></w:p>`
+	}
+	document := strings.Replace(newTestDocumentXml(drawing("rId100", `descr="logo"`)+drawing("rId101", `title="banner"`)),
+		`<w:document `, `<w:document xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" `+
+			`xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" `+
+			`xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture" `, 1)
+	rels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId100" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>` +
+		`<Relationship Id="rId101" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image2.png"/>` +
+		`</Relationships>`
+
+	return newTestDocx(t, map[string]string{
+		DocumentXml:                        document,
+		relationshipsPartName(DocumentXml): rels,
+		"word/media/image1.png":            "\x89PNG\r\n\x1a\nlogo",
+		"word/media/image2.png":            "\x89PNG\r\n\x1a\nbanner",
+	})
+}
+
+func TestDocument_ReplaceExistingImage(t *testing.T) {
+	doc, err := OpenBytes(newTestImageDocx(t))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\nnew logo")
+	if err := doc.ReplaceExistingImage("logo", ImageData{Data: png}); err != nil {
+		t.Error("replacing image by alt-text failed", err)
+		return
+	}
+	jpeg := []byte("\xff\xd8\xff\xe0new banner")
+	if err := doc.ReplaceExistingImage("banner", ImageData{Data: jpeg}); err != nil {
+		t.Error("replacing image by title failed", err)
+		return
+	}
+	if err := doc.ReplaceExistingImage("missing", ImageData{Data: png}); !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrImageNotFound, err)
+	}
+
+	if data := writtenFile(t, doc, "word/media/image1.png"); !bytes.Equal(data, png) {
+		t.Errorf("image1.png was not replaced, have=%q", data)
+	}
+	if data := writtenFile(t, doc, "word/media/image2.png"); !bytes.Equal(data, jpeg) {
+		t.Errorf("image2.png was not replaced, have=%q", data)
+	}
+
+	contentTypes := string(writtenFile(t, doc, ContentTypesXml))
+	if !strings.Contains(contentTypes, `<Override PartName="/word/media/image2.png" ContentType="image/jpeg"/>`) {
+		t.Error("expected content type of image2.png to be overridden")
+	}
+	if strings.Contains(contentTypes, `/word/media/image1.png`) {
+		t.Error("expected content type of image1.png to be unchanged")
+	}
+}
+
+func TestDocument_Images(t *testing.T) {
+	doc, err := OpenBytes(newTestImageDocx(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jpeg := []byte("\xff\xd8\xff\xe0new banner")
+	if err := doc.ReplaceExistingImage("banner", ImageData{Data: jpeg}); err != nil {
+		t.Fatal(err)
+	}
+
+	images := doc.Images()
+	if len(images) != 2 {
+		t.Fatalf("unexpected number of images, want=%d, have=%d", 2, len(images))
+	}
+	expected := []ImagePart{
+		{Name: "word/media/image1.png", ContentType: "image/png", Data: []byte("\x89PNG\r\n\x1a\nlogo")},
+		{Name: "word/media/image2.png", ContentType: "image/jpeg", Data: jpeg},
+	}
+	for i, image := range images {
+		if image.Name != expected[i].Name || image.ContentType != expected[i].ContentType || !bytes.Equal(image.Data, expected[i].Data) {
+			t.Errorf("unexpected image %d, want=%s (%s), have=%s (%s)", i, expected[i].Name, expected[i].ContentType, image.Name, image.ContentType)
+		}
+	}
+}
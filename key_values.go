@@ -0,0 +1,74 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidKeyValue is returned by ParseKeyValues if a pair cannot be parsed.
+var ErrInvalidKeyValue = errors.New("invalid key=value pair")
+
+// ParseKeyValues parses pairs like 'name=Alice' into a PlaceholderMap, e.g. from the arguments of a command line tool.
+// The pair is split at the first '=' which is not escaped. A backslash escapes '=' and itself, thus the
+// pair 'a\=b=c\\d' has the key 'a=b' and the value 'c\d'. Values enclosed in double quotes are unquoted like Go string
+// literals, e.g. '"line\nbreak"'. The keys are trimmed and must not be empty or occur twice.
+// All values are strings, they are formatted with fmt.Sprint when replacing anyway.
+func ParseKeyValues(pairs []string) (PlaceholderMap, error) {
+	placeholderMap := make(PlaceholderMap, len(pairs))
+	for _, pair := range pairs {
+		separator := keyValueSeparator(pair)
+		if separator < 0 {
+			return nil, fmt.Errorf("%w: %q has no '='", ErrInvalidKeyValue, pair)
+		}
+
+		key := strings.TrimSpace(unescapeKeyValue(pair[:separator]))
+		if key == "" {
+			return nil, fmt.Errorf("%w: %q has an empty key", ErrInvalidKeyValue, pair)
+		}
+		if _, exists := placeholderMap[key]; exists {
+			return nil, fmt.Errorf("%w: duplicate key %s", ErrInvalidKeyValue, key)
+		}
+
+		value := pair[separator+1:]
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to unquote the value of %s: %s", ErrInvalidKeyValue, key, err)
+			}
+			placeholderMap[key] = unquoted
+			continue
+		}
+		placeholderMap[key] = unescapeKeyValue(value)
+	}
+	return placeholderMap, nil
+}
+
+// keyValueSeparator returns the index of the first '=' which is not escaped, or -1.
+func keyValueSeparator(pair string) int {
+	for i := 0; i < len(pair); i++ {
+		switch pair[i] {
+		case '\\':
+			i++ // the escaped character is skipped
+		case '=':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeKeyValue removes the backslashes in front of '=' and '\'. All other backslashes are kept.
+func unescapeKeyValue(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '=' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
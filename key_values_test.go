@@ -0,0 +1,46 @@
+package docx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	placeholderMap, err := ParseKeyValues([]string{
+		"name=Alice",
+		" count = 3",
+		"formula=a=b",
+		`a\=b=c\\d`,
+		`path=C:\docs`,
+		`quoted="line\nbreak"`,
+		"empty=",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PlaceholderMap{
+		"name":    "Alice",
+		"count":   " 3",
+		"formula": "a=b",
+		"a=b":     `c\d`,
+		"path":    `C:\docs`,
+		"quoted":  "line\nbreak",
+		"empty":   "",
+	}
+	if !reflect.DeepEqual(placeholderMap, expected) {
+		t.Errorf("unexpected map, want=%v, have=%v", expected, placeholderMap)
+	}
+
+	for _, pairs := range [][]string{
+		{"name"},
+		{`name\=Alice`},
+		{"=Alice"},
+		{"name=Alice", "name=Bob"},
+		{`name="unterminated\"`},
+	} {
+		if _, err := ParseKeyValues(pairs); !errors.Is(err, ErrInvalidKeyValue) {
+			t.Errorf("unexpected error for %q, want=%v, have=%v", pairs, ErrInvalidKeyValue, err)
+		}
+	}
+}
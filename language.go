@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+)
+
+var (
+	// defaultRunPropertiesRegex matches the default properties of all runs inside the StylesXml.
+	defaultRunPropertiesRegex = regexp.MustCompile(`(?s)<w:rPrDefault>.*?</w:rPrDefault>|<w:rPrDefault/>`)
+	// emptyDocDefaultsRegex matches the document defaults inside the StylesXml if they are a self-closing tag.
+	emptyDocDefaultsRegex = regexp.MustCompile(`<w:docDefaults\s*/>`)
+	// languageRegex matches the language of run properties.
+	languageRegex = regexp.MustCompile(`<w:lang\b[^>]*/>`)
+	// themeFontLanguageRegex matches the language of the theme fonts inside the SettingsXml.
+	themeFontLanguageRegex = regexp.MustCompile(`<w:themeFontLang\b[^>]*/>`)
+	// valAttributeRegex matches the 'w:val' attribute of a tag.
+	valAttributeRegex = regexp.MustCompile(`\sw:val="[^"]*"`)
+)
+
+// Language returns the default language of the text (e.g. 'en-US') as defined by the StylesXml.
+// Runs may override it with their own language. If no default language is defined, an empty string is returned.
+func (d *Document) Language() (string, error) {
+	styles, err := d.styles()
+	if err != nil {
+		return "", err
+	}
+	if styles.DefaultLanguage == nil {
+		return "", nil
+	}
+	return styles.DefaultLanguage.Val, nil
+}
+
+// SetLanguage sets the default language of the text (e.g. 'de-DE'), which Word uses for spell checking and
+// hyphenation. The languages of east asian and complex script text are kept. If the SettingsXml defines the
+// language of the theme fonts, it is updated as well. Runs with their own language are not changed.
+func (d *Document) SetLanguage(lang string) error {
+	data, err := d.readRawFile(StylesXml)
+	if err != nil {
+		return err
+	}
+	data = copyBytes(data)
+	language := fmt.Sprintf(`<w:lang w:val="%s"/>`, html.EscapeString(lang))
+
+	var modified []byte
+	if loc := defaultRunPropertiesRegex.FindIndex(data); loc != nil {
+		defaults := setDefaultLanguage(data[loc[0]:loc[1]], lang, language)
+		modified = spliceBytes(data, int64(loc[0]), int64(loc[1]), defaults)
+	} else if loc := emptyDocDefaultsRegex.FindIndex(data); loc != nil {
+		// the self-closing document defaults are expanded in place, they must not be defined twice
+		defaults := `<w:docDefaults><w:rPrDefault><w:rPr>` + language + `</w:rPr></w:rPrDefault></w:docDefaults>`
+		modified = spliceBytes(data, int64(loc[0]), int64(loc[1]), []byte(defaults))
+	} else {
+		// the default run properties are the first of the document defaults
+		defaults := `<w:rPrDefault><w:rPr>` + language + `</w:rPr></w:rPrDefault>`
+		start := lastOpenTag(data, "w:docDefaults")
+		if start < 0 {
+			defaults = `<w:docDefaults>` + defaults + `</w:docDefaults>`
+			start = lastOpenTag(data, "w:styles")
+		}
+		if start < 0 {
+			return fmt.Errorf("unable to set language: %s has no styles element", StylesXml)
+		}
+		modified = spliceBytes(data, int64(start), int64(start), []byte(defaults))
+	}
+	d.setRawFile(StylesXml, modified)
+
+	settings, err := d.readRawFile(SettingsXml)
+	if err != nil || !themeFontLanguageRegex.Match(settings) {
+		return nil
+	}
+	settings = themeFontLanguageRegex.ReplaceAllFunc(settings, func(tag []byte) []byte {
+		return setValAttribute(tag, lang)
+	})
+	d.setRawFile(SettingsXml, settings)
+	return nil
+}
+
+// setDefaultLanguage sets the language inside the default run properties (<w:rPrDefault>).
+func setDefaultLanguage(defaults []byte, lang, language string) []byte {
+	if languageRegex.Match(defaults) {
+		return languageRegex.ReplaceAllFunc(defaults, func(tag []byte) []byte {
+			return setValAttribute(tag, lang)
+		})
+	}
+	if closing := bytes.Index(defaults, []byte(`</w:rPr>`)); closing >= 0 {
+		return spliceBytes(copyBytes(defaults), int64(closing), int64(closing), []byte(language))
+	}
+	return []byte(`<w:rPrDefault><w:rPr>` + language + `</w:rPr></w:rPrDefault>`)
+}
+
+// setValAttribute sets the 'w:val' attribute of the singleton tag, all other attributes are kept.
+func setValAttribute(tag []byte, val string) []byte {
+	attribute := fmt.Sprintf(` w:val="%s"`, html.EscapeString(val))
+	if valAttributeRegex.Match(tag) {
+		return valAttributeRegex.ReplaceAllLiteral(tag, []byte(attribute))
+	}
+	end := int64(len(tag) - len("/>"))
+	return spliceBytes(copyBytes(tag), end, end, []byte(attribute))
+}
@@ -0,0 +1,69 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_SetLanguage(t *testing.T) {
+	settings := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:themeFontLang w:val="en-GB" w:eastAsia="zh-CN"/></w:settings>`
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{SettingsXml: settings}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang, err := doc.Language(); err != nil || lang != "en-gb" {
+		t.Fatalf("unexpected language, want=%s, have=%s (%v)", "en-gb", lang, err)
+	}
+
+	if err := doc.SetLanguage("de-DE"); err != nil {
+		t.Fatal(err)
+	}
+	if lang, err := doc.Language(); err != nil || lang != "de-DE" {
+		t.Errorf("unexpected language, want=%s, have=%s (%v)", "de-DE", lang, err)
+	}
+	if styles := string(writtenFile(t, doc, StylesXml)); !strings.Contains(styles, `<w:lang w:val="de-DE" w:eastAsia="zh-cn" w:bidi="ar-sa"/>`) {
+		t.Error("expected the default language to be replaced, keeping the other languages")
+	}
+	if settings := string(writtenFile(t, doc, SettingsXml)); !strings.Contains(settings, `<w:themeFontLang w:val="de-DE" w:eastAsia="zh-CN"/>`) {
+		t.Errorf("language of the theme fonts was not replaced, have=%s", settings)
+	}
+}
+
+func TestDocument_SetLanguage_WithoutDefaults(t *testing.T) {
+	tests := map[string]string{
+		"no language": `<w:docDefaults><w:rPrDefault><w:rPr><w:sz w:val="20"/></w:rPr></w:rPrDefault></w:docDefaults>`,
+		"no rPr":      `<w:docDefaults><w:pPrDefault/></w:docDefaults>`,
+		"no defaults": ``,
+		"empty rPr":   `<w:docDefaults><w:rPrDefault/></w:docDefaults>`,
+		"empty":       `<w:docDefaults/>`,
+		"empty space": `<w:docDefaults />`,
+	}
+	for name, defaults := range tests {
+		t.Run(name, func(t *testing.T) {
+			styles := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+				`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + defaults +
+				`<w:style w:type="paragraph" w:styleId="Normal"/></w:styles>`
+			doc, err := OpenBytes(newTestDocx(t, map[string]string{StylesXml: styles}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lang, err := doc.Language(); err != nil || lang != "" {
+				t.Fatalf("unexpected language, want=%q, have=%q (%v)", "", lang, err)
+			}
+			if err := doc.SetLanguage("fr-FR"); err != nil {
+				t.Fatal(err)
+			}
+			if lang, err := doc.Language(); err != nil || lang != "fr-FR" {
+				t.Errorf("unexpected language, want=%s, have=%s (%v)", "fr-FR", lang, err)
+			}
+			if ids, err := doc.Styles(); err != nil || len(ids) != 1 {
+				t.Errorf("styles were not kept, have=%q (%v)", ids, err)
+			}
+			if count := strings.Count(string(writtenFile(t, doc, StylesXml)), "<w:docDefaults"); count != 1 {
+				t.Errorf("unexpected number of document defaults, want=%d, have=%d", 1, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValueType describes the value which the code provides for a placeholder.
+// The types can be combined, e.g. 'TextValue | OptionalValue' or 'DateValue | TextValue' for a value which is
+// either a date or a text. Lint reports placeholders whose type cannot apply where they are placed.
+type ValueType int
+
+const (
+	// TextValue is an arbitrary text.
+	TextValue ValueType = 1 << iota
+	// NumberValue is a formatted number.
+	NumberValue
+	// DateValue is a formatted date.
+	DateValue
+	// OptionalValue marks placeholders which do not need to exist in the template.
+	OptionalValue
+)
+
+// LintErrorKind classifies the problems found by LintTemplate.
+type LintErrorKind string
+
+const (
+	// LintUnknownPlaceholder is reported for placeholders of the template which are not part of the schema.
+	LintUnknownPlaceholder LintErrorKind = "unknown-placeholder"
+	// LintMissingPlaceholder is reported for required placeholders of the schema which do not exist in the template.
+	LintMissingPlaceholder LintErrorKind = "missing-placeholder"
+	// LintIrregularWhitespace is reported for placeholders which contain leading, trailing, zero-width or
+	// non-breaking spaces. Such placeholders are only matched with SetNormalizeWhitespace.
+	LintIrregularWhitespace LintErrorKind = "irregular-whitespace"
+	// LintUnparsablePlaceholder is reported for placeholders which are visible in the text, but could not be
+	// parsed (e.g. nested placeholders) and thus cannot be replaced.
+	LintUnparsablePlaceholder LintErrorKind = "unparsable-placeholder"
+	// LintTypeMismatch is reported for placeholders whose ValueType cannot apply where they are placed,
+	// e.g. a NumberValue placeholder inside a date picker content control, which only accepts dates.
+	LintTypeMismatch LintErrorKind = "type-mismatch"
+)
+
+// LintError is a single problem found by LintTemplate.
+type LintError struct {
+	Kind LintErrorKind
+	// File is the file of the archive in which the problem was found.
+	// It is empty for problems which do not belong to a file (e.g. missing placeholders).
+	File string
+	// Placeholder is the affected placeholder, including its delimiters.
+	Placeholder string
+}
+
+// Error implements the error interface.
+func (e LintError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s: %s", e.Kind, e.Placeholder)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Kind, e.Placeholder)
+}
+
+// LintTemplate opens the template and checks its placeholders against the schema which maps the
+// placeholder keys (without delimiters) to their expected ValueType. See Document.Lint.
+func LintTemplate(path string, schema map[string]ValueType) ([]LintError, error) {
+	doc, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	return doc.Lint(schema), nil
+}
+
+// Lint checks the placeholders of the document against the schema. It reports placeholders which are unknown to the
+// schema, required placeholders of the schema which are missing, placeholders whose type does not fit their position
+// as well as placeholders which cannot be replaced reliably.
+// The problems are returned in the order of the files (document, headers, footers).
+func (d *Document) Lint(schema map[string]ValueType) []LintError {
+	var lintErrors []LintError
+	found := make(map[string]bool)
+
+	for _, file := range d.parsedFiles() {
+		dateControls := dateContentControls(d.files[file])
+		parsed := make(map[string]int)
+		mismatched := make(map[string]bool)
+		for _, placeholder := range d.filePlaceholders[file] {
+			text := placeholder.Text(d.files[file])
+			normalized := d.delimiters.normalize(text)
+			parsed[normalized]++
+
+			// the type is checked for every occurrence, the placeholder may be placed differently each time
+			valueType, known := schema[d.delimiters.remove(normalized)]
+			if known && valueType&DateValue == 0 && !mismatched[normalized] && containsOffset(dateControls, placeholder.StartPos()) {
+				mismatched[normalized] = true
+				lintErrors = append(lintErrors, LintError{Kind: LintTypeMismatch, File: file, Placeholder: text})
+			}
+
+			if parsed[normalized] > 1 {
+				continue
+			}
+
+			if normalized != text {
+				lintErrors = append(lintErrors, LintError{Kind: LintIrregularWhitespace, File: file, Placeholder: text})
+			}
+
+			key := d.delimiters.remove(normalized)
+			found[key] = true
+			if _, known := schema[key]; !known {
+				lintErrors = append(lintErrors, LintError{Kind: LintUnknownPlaceholder, File: file, Placeholder: text})
+			}
+		}
+
+		// whitespace is compared normalized since the plaintext is trimmed per text-run
+		visible := make(map[string]int)
+		for text, count := range countDelimitedPlaceholders(d.stripXmlTags(string(d.files[file])), d.delimiters) {
+			visible[d.delimiters.normalize(text)] += count
+		}
+		var unparsable []string
+		for text, count := range visible {
+			if count > parsed[text] {
+				unparsable = append(unparsable, text)
+			}
+		}
+		sort.Strings(unparsable)
+		for _, text := range unparsable {
+			lintErrors = append(lintErrors, LintError{Kind: LintUnparsablePlaceholder, File: file, Placeholder: text})
+		}
+	}
+
+	var keys []string
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !found[key] && schema[key]&OptionalValue == 0 {
+			lintErrors = append(lintErrors, LintError{Kind: LintMissingPlaceholder, Placeholder: d.delimiters.add(key)})
+		}
+	}
+
+	return lintErrors
+}
+
+// dateContentControls returns the positions of the content of all date picker content controls inside the data.
+// If the content controls cannot be parsed, no positions are returned.
+func dateContentControls(data []byte) []Position {
+	controls, err := parseContentControls(data)
+	if err != nil {
+		return nil
+	}
+	var positions []Position
+	for _, control := range controls {
+		if control.date {
+			positions = append(positions, Position{Start: control.content.OpenTag.End, End: control.content.CloseTag.Start})
+		}
+	}
+	return positions
+}
+
+// containsOffset returns true if one of the positions contains the offset.
+func containsOffset(positions []Position, offset int64) bool {
+	for _, position := range positions {
+		if offset >= position.Start && offset < position.End {
+			return true
+		}
+	}
+	return false
+}
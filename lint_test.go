@@ -0,0 +1,81 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_Lint(t *testing.T) {
+	dateControl := func(content string) string {
+		return `<w:sdt><w:sdtPr><w:tag w:val="due"/><w:date w:fullDate="2024-01-01T00:00:00Z">` +
+			`<w:dateFormat w:val="dd.MM.yyyy"/></w:date></w:sdtPr><w:sdtContent>` + content + `</w:sdtContent></w:sdt>`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		schema   map[string]ValueType
+		expected []LintError
+	}{
+		{
+			name: "placeholders",
+			body: `<w:p><w:r><w:t>{name} {name} {unknown} {date` + " " + `}</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>{foo{nested}}</w:t></w:r></w:p>`,
+			schema: map[string]ValueType{
+				"name":     TextValue,
+				"date":     DateValue,
+				"nested":   TextValue,
+				"total":    NumberValue,
+				"optional": TextValue | OptionalValue,
+				"key":      TextValue,
+			},
+			expected: []LintError{
+				{Kind: LintUnknownPlaceholder, File: DocumentXml, Placeholder: "{unknown}"},
+				{Kind: LintIrregularWhitespace, File: DocumentXml, Placeholder: "{date }"},
+				{Kind: LintUnparsablePlaceholder, File: DocumentXml, Placeholder: "{nested}"},
+				{Kind: LintMissingPlaceholder, Placeholder: "{nested}"},
+				{Kind: LintMissingPlaceholder, Placeholder: "{total}"},
+			},
+		},
+		{
+			name: "type mismatch",
+			body: dateControl(`<w:p><w:r><w:t>{total} {due} {note} {either}</w:t></w:r></w:p>`) +
+				`<w:p><w:r><w:t>{total} {due}</w:t></w:r></w:p>`,
+			schema: map[string]ValueType{
+				"total":  NumberValue,
+				"due":    DateValue,
+				"note":   TextValue,
+				"either": DateValue | TextValue,
+				"key":    TextValue,
+			},
+			expected: []LintError{
+				{Kind: LintTypeMismatch, File: DocumentXml, Placeholder: "{total}"},
+				{Kind: LintTypeMismatch, File: DocumentXml, Placeholder: "{note}"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, tt.body)
+			if lintErrors := doc.Lint(tt.schema); !reflect.DeepEqual(lintErrors, tt.expected) {
+				t.Errorf("unexpected lint errors, want=%v, have=%v", tt.expected, lintErrors)
+			}
+		})
+	}
+}
+
+func TestLintTemplate(t *testing.T) {
+	lintErrors, err := LintTemplate("./test/template.docx", map[string]ValueType{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(lintErrors) == 0 {
+		t.Error("expected the placeholders of the template to be reported as unknown")
+	}
+	for _, lintError := range lintErrors {
+		if lintError.Kind != LintUnknownPlaceholder {
+			t.Errorf("unexpected lint error, want=%s, have=%s", LintUnknownPlaceholder, lintError)
+		}
+	}
+}
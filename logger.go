@@ -0,0 +1,26 @@
+package docx
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+var (
+	logger Logger = log.New(os.Stderr, "", log.LstdFlags) // package-wide logger, used for all diagnostic output
+)
+
+// Logger is the interface used for all diagnostic output of the package.
+// The *log.Logger of the standard library satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetLogger replaces the logger which is used for all diagnostic output.
+// Passing nil discards all output.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = log.New(ioutil.Discard, "", 0)
+	}
+	logger = l
+}
@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// placeholderMarker temporarily takes the place of a placeholder until it is replaced by markup, e.g. a drawing.
+// It cannot be part of any valid XML, thus it never collides with the content of the document.
+const placeholderMarker = "\x00placeholder\x00"
+
+// markedFile is the content of a file in which placeholders were replaced by the placeholderMarker.
+type markedFile struct {
+	name string
+	data []byte
+}
+
+// markPlaceholders replaces all placeholders with the given key by the placeholderMarker and returns the content
+// of the files which contain at least one of them. The placeholders are marked on a clone, the document itself is
+// not modified. Once the markers are replaced, the files must be set and parsed again.
+//
+// If no placeholder with the given key exists, ErrPlaceholderNotFound is returned.
+func (d *Document) markPlaceholders(key string) ([]markedFile, error) {
+	// the replacers modify their data, thus a failing file must not leave markers behind in the document
+	clone := d.Clone()
+
+	var files []markedFile
+	for _, name := range clone.allParsedFiles() {
+		if err := clone.fileReplacers[name].Replace(key, placeholderMarker); err != nil {
+			if errors.Is(err, ErrPlaceholderNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		files = append(files, markedFile{name: name, data: clone.fileReplacers[name].Bytes()})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPlaceholderNotFound, key)
+	}
+	return files, nil
+}
+
+// replaceMarkers replaces every placeholderMarker inside the data by the markup returned for its position.
+// The marker is always inside a text-run, thus the markup usually closes the text (and run) it is placed in.
+func replaceMarkers(data []byte, markup func(data []byte, pos int) string) []byte {
+	var modified []byte
+	for {
+		pos := bytes.Index(data, []byte(placeholderMarker))
+		if pos < 0 {
+			break
+		}
+		modified = append(modified, data[:pos]...)
+		modified = append(modified, []byte(markup(data, pos))...)
+		data = data[pos+len(placeholderMarker):]
+	}
+	return append(modified, data...)
+}
+
+// rootElementName returns the qualified name of the root element of the XML data, e.g. 'w:document'.
+func rootElementName(data []byte) string {
+	for pos := 0; ; {
+		start := bytes.IndexByte(data[pos:], '<')
+		if start < 0 || pos+start+1 >= len(data) {
+			return ""
+		}
+		start += pos + 1
+		if data[start] != '?' && data[start] != '!' {
+			end := bytes.IndexAny(data[start:], " \t\r\n/>")
+			if end < 0 {
+				return ""
+			}
+			return string(data[start : start+end])
+		}
+		pos = start
+	}
+}
@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Matcher decides whether the text of a placeholder matches a key.
+// Both the placeholder and the key are passed including their delimiters, e.g. '{foo}'.
+// Matchers always receive OpenDelimiter and CloseDelimiter, even if the document uses other delimiters.
+type Matcher interface {
+	Match(placeholder, key string) bool
+}
+
+// MatcherFunc is an adapter to use an ordinary function as Matcher.
+type MatcherFunc func(placeholder, key string) bool
+
+// Match calls f(placeholder, key).
+func (f MatcherFunc) Match(placeholder, key string) bool {
+	return f(placeholder, key)
+}
+
+var (
+	// ExactMatcher matches if the placeholder and the key are equal. It is used by default.
+	ExactMatcher Matcher = MatcherFunc(func(placeholder, key string) bool {
+		return placeholder == key
+	})
+	// CaseInsensitiveMatcher matches if the placeholder and the key are equal under Unicode case-folding.
+	CaseInsensitiveMatcher Matcher = MatcherFunc(strings.EqualFold)
+	// WhitespaceMatcher matches if the placeholder and the key are equal after normalizing their whitespace.
+	// See NormalizePlaceholder for the applied normalization.
+	WhitespaceMatcher Matcher = MatcherFunc(func(placeholder, key string) bool {
+		return NormalizePlaceholder(placeholder) == NormalizePlaceholder(key)
+	})
+)
+
+// RegexMatcher returns a Matcher which treats the keys as regular expressions.
+// The expression must match the whole placeholder without its delimiters, e.g. the key 'item\.\d+' matches
+// the placeholder '{item.1}'. Keys which are no valid regular expression never match.
+func RegexMatcher() Matcher {
+	var cache sync.Map // key => *regexp.Regexp, nil if the key is invalid
+	return MatcherFunc(func(placeholder, key string) bool {
+		key = defaultDelimiters.trim(key)
+		cached, ok := cache.Load(key)
+		if !ok {
+			regex, err := regexp.Compile(`^(?:` + key + `)$`)
+			if err != nil {
+				regex = nil
+			}
+			cached, _ = cache.LoadOrStore(key, regex)
+		}
+		regex := cached.(*regexp.Regexp)
+		return regex != nil && regex.MatchString(defaultDelimiters.trim(placeholder))
+	})
+}
+
+// SetMatcher sets the Matcher which decides which placeholders are replaced by a key of the PlaceholderMap.
+// Setting a nil Matcher restores the default, which is ExactMatcher or WhitespaceMatcher if SetNormalizeWhitespace is enabled.
+func (d *Document) SetMatcher(matcher Matcher) {
+	d.matcher = matcher
+	for _, replacer := range d.fileReplacers {
+		replacer.Matcher = matcher
+	}
+}
+
+// effectiveMatcher returns the matcher if it is set and the default matcher for the normalize setting otherwise.
+func effectiveMatcher(matcher Matcher, normalizeWhitespace bool) Matcher {
+	if matcher != nil {
+		return matcher
+	}
+	if normalizeWhitespace {
+		return WhitespaceMatcher
+	}
+	return ExactMatcher
+}
@@ -0,0 +1,75 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name        string
+		matcher     Matcher
+		placeholder string
+		key         string
+		expected    bool
+	}{
+		{name: "exact", matcher: ExactMatcher, placeholder: "{foo}", key: "{foo}", expected: true},
+		{name: "exact case", matcher: ExactMatcher, placeholder: "{Foo}", key: "{foo}", expected: false},
+		{name: "case insensitive", matcher: CaseInsensitiveMatcher, placeholder: "{FOO}", key: "{foo}", expected: true},
+		{name: "whitespace", matcher: WhitespaceMatcher, placeholder: "{ foo }", key: "{foo}", expected: true},
+		{name: "whitespace inner", matcher: WhitespaceMatcher, placeholder: "{fo o}", key: "{foo}", expected: false},
+		{name: "regex", matcher: RegexMatcher(), placeholder: "{item.12}", key: `{item\.\d+}`, expected: true},
+		{name: "regex whole placeholder", matcher: RegexMatcher(), placeholder: "{item.12.name}", key: `{item\.\d+}`, expected: false},
+		{name: "regex with delimiters", matcher: RegexMatcher(), placeholder: "{year-2024}", key: `year-\d{4}`, expected: true},
+		{name: "regex invalid", matcher: RegexMatcher(), placeholder: "{foo}", key: "{foo(}", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if match := tt.matcher.Match(tt.placeholder, tt.key); match != tt.expected {
+				t.Errorf("unexpected match of %s and %s, want=%v, have=%v", tt.placeholder, tt.key, tt.expected, match)
+			}
+		})
+	}
+}
+
+func TestDocument_SetMatcher(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">{Name} and {NAME}, {item.1} and {item.2}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	doc.SetMatcher(CaseInsensitiveMatcher)
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	doc.SetMatcher(RegexMatcher())
+	if err := doc.ReplaceAll(PlaceholderMap{`item\.\d`: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Jane and Jane, x and x"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
+
+func TestDocument_SetMatcher_CustomDelimiters(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">« foo » and «BAR»</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	if err := doc.SetDelimiters('«', '»'); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.SetNormalizeWhitespace(true)
+	if err := doc.ReplaceAll(PlaceholderMap{"foo": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	doc.SetNormalizeWhitespace(false)
+	doc.SetMatcher(CaseInsensitiveMatcher)
+	if err := doc.ReplaceAll(PlaceholderMap{"bar": "y"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "x and y"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
@@ -0,0 +1,127 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+)
+
+var (
+	// proofErrorRegex matches the spelling and grammar markers Word places between runs.
+	proofErrorRegex = regexp.MustCompile(`<w:proofErr\b[^>]*/>`)
+	// interTagWhitespaceRegex matches the whitespace between two tags.
+	interTagWhitespaceRegex = regexp.MustCompile(`>\s+<`)
+)
+
+// MergeRuns merges consecutive runs with identical properties (<w:rPr>) into a single run.
+// Word frequently splits text into several runs, e.g. due to spell checking or editing sessions, which is why
+// placeholders end up being fragmented. Merging them reduces the fragmentation before replacing.
+//
+// Only runs which consist of nothing but their properties and a single text are merged. The attributes of the first
+// run (e.g. the revision ids) are kept and the spelling and grammar markers (<w:proofErr>) between merged runs are
+// moved behind the merged run. The visible text is never changed.
+//
+// It is optional and should be called before replacing, all modified files are parsed again afterwards.
+func (d *Document) MergeRuns() error {
+	for name, data := range d.files {
+		parser, exists := d.runParsers[name]
+		if !exists {
+			continue
+		}
+		edits := mergeRunsEdits(data, parser.Runs())
+		if len(edits) == 0 {
+			continue
+		}
+
+		d.files[name] = applyEdits(data, edits)
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeRunsEdits returns the edits which merge all groups of consecutive, mergeable runs with the same properties.
+func mergeRunsEdits(data []byte, runs DocumentRuns) (edits []byteEdit) {
+	sorted := append(DocumentRuns(nil), runs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].OpenTag.Start < sorted[j].OpenTag.Start })
+
+	var group []*Run
+	var groupProperties []byte
+	flush := func() {
+		if len(group) > 1 {
+			edits = append(edits, mergedRunsEdit(data, group))
+		}
+		group = nil
+	}
+
+	for _, run := range sorted {
+		properties, ok := mergeableRunProperties(data, run)
+		if !ok {
+			flush()
+			continue
+		}
+		if len(group) > 0 {
+			last := group[len(group)-1]
+			if !bytes.Equal(properties, groupProperties) || !isRunGap(data, last.CloseTag.End, run.OpenTag.Start) {
+				flush()
+			}
+		}
+		if len(group) == 0 {
+			groupProperties = properties
+		}
+		group = append(group, run)
+	}
+	flush()
+	return edits
+}
+
+// mergedRunsEdit returns the edit which replaces the runs with the first run containing the text of all runs.
+func mergedRunsEdit(data []byte, runs []*Run) byteEdit {
+	first, last := runs[0], runs[len(runs)-1]
+
+	var merged bytes.Buffer
+	merged.Write(data[first.OpenTag.Start:first.Text.OpenTag.Start])
+	merged.WriteString(`<w:t xml:space="preserve">`)
+	for _, run := range runs {
+		merged.Write(data[run.Text.OpenTag.End:run.Text.CloseTag.Start])
+	}
+	merged.Write(data[first.Text.CloseTag.Start:first.CloseTag.End])
+
+	// proofing markers are kept behind the merged run so that their start and end markers stay paired
+	for i := 1; i < len(runs); i++ {
+		for _, marker := range proofErrorRegex.FindAll(data[runs[i-1].CloseTag.End:runs[i].OpenTag.Start], -1) {
+			merged.Write(marker)
+		}
+	}
+	return byteEdit{start: first.OpenTag.Start, end: last.CloseTag.End, data: merged.Bytes()}
+}
+
+// mergeableRunProperties returns the normalized properties of the run if it can be merged.
+// A run can be merged if it contains nothing but its properties and a single text.
+func mergeableRunProperties(data []byte, run *Run) ([]byte, bool) {
+	if !run.HasText || run.OpenTag == run.CloseTag || !TextOpenTagRegex.Match(data[run.Text.OpenTag.Start:run.Text.OpenTag.End]) {
+		return nil, false
+	}
+	head, okHead := safeSlice(data, run.OpenTag.End, run.Text.OpenTag.Start)
+	tail, okTail := safeSlice(data, run.Text.CloseTag.End, run.CloseTag.Start)
+	if !okHead || !okTail || len(bytes.TrimSpace(tail)) > 0 {
+		return nil, false
+	}
+
+	head = bytes.TrimSpace(head)
+	properties := runPropertiesElement.Find(head)
+	if len(properties) != len(head) {
+		return nil, false
+	}
+	return interTagWhitespaceRegex.ReplaceAll(properties, []byte("><")), true
+}
+
+// isRunGap returns true if there is nothing but whitespace and proofing markers between the two offsets.
+func isRunGap(data []byte, start, end int64) bool {
+	gap, ok := safeSlice(data, start, end)
+	if !ok {
+		return false
+	}
+	return len(bytes.TrimSpace(proofErrorRegex.ReplaceAll(gap, nil))) == 0
+}
@@ -0,0 +1,55 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_MergeRuns(t *testing.T) {
+	template := readFile(t, "./test/fragmented_runs.xml")
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	textBefore := writtenText(t, doc, DocumentXml)
+	fragmentsBefore := len(doc.filePlaceholders[DocumentXml][0].Fragments)
+
+	if err := doc.MergeRuns(); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != textBefore {
+		t.Errorf("visible text was not preserved, want=%q, have=%q", textBefore, text)
+	}
+
+	placeholders := doc.filePlaceholders[DocumentXml]
+	if len(placeholders) != 2 {
+		t.Fatalf("unexpected number of placeholders, want=%d, have=%d", 2, len(placeholders))
+	}
+	if fragmentsBefore != 2 || len(placeholders[0].Fragments) != 1 {
+		t.Errorf("{clientName} was not merged into a single fragment, want=%d, have=%d (before %d)", 1,
+			len(placeholders[0].Fragments), fragmentsBefore)
+	}
+	// the run with the tab cannot be merged
+	if len(placeholders[1].Fragments) != 2 {
+		t.Errorf("{ref} was merged, have=%d fragments", len(placeholders[1].Fragments))
+	}
+
+	data := string(doc.GetFile(DocumentXml))
+	if strings.Contains(data, "00B2") || strings.Count(data, "proofErr") != 2 {
+		t.Errorf("runs of {clientName} were not merged into the first run, have=%s", data)
+	}
+	if !strings.Contains(data, `<w:t xml:space="preserve"> &amp; more</w:t>`) {
+		t.Errorf("runs without properties were not merged, have=%s", data)
+	}
+
+	if err := doc.ReplaceAll(PlaceholderMap{"clientName": "ACME", "ref": "42"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Dear ACME and 42 & more"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+	if err := doc.ValidateOutput(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+)
+
+// skeletonFiles are the files of the minimal docx created by New.
+var skeletonFiles = []struct {
+	name    string
+	content string
+}{
+	{
+		name: ContentTypesXml,
+		content: XmlDeclaration + "\n" +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="` + RelationshipsContentType + `"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: PackageRelationshipsXml,
+		content: XmlDeclaration + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: DocumentXml,
+		content: XmlDeclaration + "\n" +
+			`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<w:body><w:sectPr><w:pgSz w:w="12240" w:h="15840"/>` +
+			`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>` +
+			`</w:sectPr></w:body></w:document>`,
+	},
+}
+
+// New creates a minimal, empty docx document in memory. It only consists of the document with a single section,
+// paragraphs can be added using AppendParagraph. The document is written just like an opened one.
+func New() *Document {
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range skeletonFiles {
+		fw, err := zipWriter.Create(file.name)
+		if err != nil {
+			panic(fmt.Sprintf("unable to create %s: %s", file.name, err))
+		}
+		if _, err := fw.Write([]byte(file.content)); err != nil {
+			panic(fmt.Sprintf("unable to write %s: %s", file.name, err))
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		panic(fmt.Sprintf("unable to close zip writer: %s", err))
+	}
+
+	// the skeleton is static, thus it cannot fail to open
+	doc, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		panic(fmt.Sprintf("unable to open skeleton: %s", err))
+	}
+	return doc
+}
+
+// AppendParagraph adds a paragraph with the given text to the end of the document body.
+// Placeholders inside the text are parsed and can be replaced afterwards.
+func (d *Document) AppendParagraph(text string) error {
+	document := d.files[DocumentXml]
+	insertPos := bytes.LastIndex(document, []byte("</w:body>"))
+	if insertPos < 0 {
+		return fmt.Errorf("unable to find the end of the body in %s", DocumentXml)
+	}
+	// the section properties of the body must stay its last element, unlike the ones of paragraphs
+	if sectPr := bytes.LastIndex(document[:insertPos], []byte("<w:sectPr")); sectPr >= 0 &&
+		!bytes.Contains(document[sectPr:insertPos], []byte("</w:p>")) {
+		insertPos = sectPr
+	}
+
+	paragraph := `<w:p><w:r><w:t xml:space="preserve">` + html.EscapeString(text) + `</w:t></w:r></w:p>`
+	var modified []byte
+	modified = append(modified, document[:insertPos]...)
+	modified = append(modified, []byte(paragraph)...)
+	modified = append(modified, document[insertPos:]...)
+
+	d.files[DocumentXml] = modified
+	return d.parseFile(DocumentXml)
+}
@@ -0,0 +1,39 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	doc := New()
+	for _, text := range []string{"Dear {name},", "thanks for your order of {amount} € & more."} {
+		if err := doc.AppendParagraph(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane", "amount": "42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal("written document cannot be opened", err)
+	}
+	if err := written.ValidateOutput(); err != nil {
+		t.Error(err)
+	}
+
+	if text := writtenText(t, written, DocumentXml); text != "Dear Jane,thanks for your order of 42 € & more." {
+		t.Errorf("unexpected text, want=%q, have=%q", "Dear Jane,thanks for your order of 42 € & more.", text)
+	}
+	document := string(writtenFile(t, written, DocumentXml))
+	if !strings.HasSuffix(document, "</w:sectPr></w:body></w:document>") {
+		t.Errorf("section properties are not the last element of the body, have=%s", document)
+	}
+}
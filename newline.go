@@ -0,0 +1,227 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NewlineMode defines how newlines inside replaced values are written.
+type NewlineMode int
+
+const (
+	// NewlineKeep writes newlines as they are. Word renders them as a space.
+	NewlineKeep NewlineMode = iota
+	// NewlineBreak writes every newline as a line break (<w:br/>).
+	NewlineBreak
+	// NewlineParagraph starts a new paragraph for every blank line (\n\n) and writes all other newlines
+	// as a line break. The new paragraphs and runs have the same properties as the ones they are created from.
+	NewlineParagraph
+)
+
+var (
+	// paragraphPropertiesElement matches the properties of a paragraph which directly follow its open tag.
+	paragraphPropertiesElement = leadingElement("w:pPr")
+	// runPropertiesElement matches the properties of a run which directly follow its open tag.
+	runPropertiesElement = leadingElement("w:rPr")
+	// sectionPropertiesRegex matches the section properties which may be part of the paragraph properties.
+	sectionPropertiesRegex = regexp.MustCompile(`(?s)<w:sectPr\b.*?</w:sectPr>|<w:sectPr\b[^>]*/>`)
+	// elementTagRegex matches open, close and empty-element tags. The submatches are the slash of a close tag,
+	// the name of the element and the slash of an empty-element tag.
+	elementTagRegex = regexp.MustCompile(`<(/?)([\w:.-]+)[^>]*?(/?)>`)
+)
+
+// newlineMarker takes the place of the newlines inside replaced values until they are converted into markup.
+// It cannot be part of any valid XML, thus the newlines of the template itself are never converted.
+const newlineMarker = "\x00newline\x00"
+
+// SetNewlineMode sets how newlines inside the replaced values are written. It is NewlineKeep by default.
+// The mode applies to the values replaced afterwards, newlines which are part of the template are kept.
+// Files in which newlines were converted are parsed again.
+func (d *Document) SetNewlineMode(mode NewlineMode) {
+	d.newlineMode = mode
+}
+
+// markNewlines replaces the newlines of the value by the newlineMarker, unless the newlines are kept.
+func (d *Document) markNewlines(value string) string {
+	if d.newlineMode == NewlineKeep {
+		return value
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\n", newlineMarker)
+}
+
+// convertNewlines converts the newline markers of the replaced file into markup and parses the file again.
+// The current content of the file is returned, files without markers are not modified.
+func (d *Document) convertNewlines(file string) ([]byte, error) {
+	data := d.fileReplacers[file].Bytes()
+	if !bytes.Contains(data, []byte(newlineMarker)) {
+		return data, nil
+	}
+	// the converted data is only stored once it can be parsed, the file must never become malformed
+	converted := convertNewlineMarkers(data, d.newlineMode)
+	if err := NewRunParser(converted).Execute(); err != nil {
+		return nil, fmt.Errorf("unable to convert the newlines of %s: %w", file, err)
+	}
+	d.files[file] = converted
+	if err := d.parseFile(file); err != nil {
+		return nil, err
+	}
+	return d.files[file], nil
+}
+
+// convertNewlineMarkers converts the newline markers inside the data according to the mode.
+func convertNewlineMarkers(data []byte, mode NewlineMode) []byte {
+	marker := []byte(newlineMarker)
+	lineBreak := []byte(`</w:t><w:br/><w:t xml:space="preserve">`)
+
+	var out []byte
+	for {
+		pos := bytes.Index(data, marker)
+		if pos < 0 {
+			break
+		}
+		out = append(out, data[:pos]...)
+		data = data[pos+len(marker):]
+
+		// the text in front of the break must keep its trailing whitespace
+		out = preserveSpace(out)
+		if mode == NewlineParagraph && bytes.HasPrefix(data, marker) {
+			// the converted data in front of the marker holds the paragraph and run it is placed in
+			out = append(out, newParagraphBreak(out, len(out))...)
+			data = data[len(marker):]
+			continue
+		}
+		out = append(out, lineBreak...)
+	}
+	return append(out, data...)
+}
+
+// preserveSpace adds xml:space="preserve" to the last open tag of a text inside the data, if it is missing.
+func preserveSpace(data []byte) []byte {
+	end := lastOpenTag(data, "w:t")
+	if end < 0 {
+		return data
+	}
+	start := bytes.LastIndex(data[:end], []byte("<w:t"))
+	if bytes.Contains(data[start:end], []byte("xml:space")) {
+		return data
+	}
+	return append([]byte(string(data[:start])+`<w:t xml:space="preserve">`), data[end:]...)
+}
+
+// newParagraphBreak returns the markup which ends the text, run and paragraph enclosing the offset and
+// starts new ones with the same properties. Section properties are not copied, the section break stays
+// with the original paragraph.
+//
+// If the run is not a direct child of the paragraph, e.g. inside a <w:hyperlink>, <w:ins> or <w:sdtContent>,
+// the paragraph cannot be ended without ending these elements as well, thus a line break is returned instead.
+func newParagraphBreak(data []byte, offset int) []byte {
+	paragraph := lastOpenTag(data[:offset], "w:p")
+	run := lastOpenTag(data[:offset], "w:r")
+	if paragraph < 0 || run < paragraph {
+		return []byte(`</w:t><w:br/><w:t xml:space="preserve">`)
+	}
+	if runStart := bytes.LastIndex(data[:run], []byte("<w:r")); !closesAllElements(data[paragraph:runStart]) {
+		return []byte(`</w:t><w:br/><w:t xml:space="preserve">`)
+	}
+
+	var paragraphProperties []byte
+	if props := paragraphPropertiesElement.Find(data[paragraph:offset]); props != nil {
+		paragraphProperties = sectionPropertiesRegex.ReplaceAll(bytes.TrimSpace(props), nil)
+	}
+	runProperties := bytes.TrimSpace(runPropertiesElement.Find(data[run:offset]))
+
+	var b bytes.Buffer
+	b.WriteString(`</w:t></w:r></w:p><w:p>`)
+	b.Write(paragraphProperties)
+	b.WriteString(`<w:r>`)
+	b.Write(runProperties)
+	b.WriteString(`<w:t xml:space="preserve">`)
+	return b.Bytes()
+}
+
+// closesAllElements returns true if every element which is opened inside the data is closed inside it as well.
+func closesAllElements(data []byte) bool {
+	depth := 0
+	for _, tag := range elementTagRegex.FindAllSubmatch(data, -1) {
+		switch {
+		case len(tag[3]) > 0:
+			// an empty element (e.g. <w:b/>) neither opens nor closes anything
+		case len(tag[1]) > 0:
+			depth--
+		default:
+			depth++
+		}
+	}
+	return depth == 0
+}
+
+// leadingElement is the name of an element which is expected right at the start of some data, e.g. the
+// properties (<w:rPr>) which directly follow the open tag of a run.
+type leadingElement string
+
+// FindIndex returns the position of the element including the whitespace in front of it, or nil if the data
+// does not start with the element. Nested elements of the same name (e.g. the previous properties inside of
+// <w:rPrChange>) are skipped, the position ends with the close tag of the outermost element.
+func (name leadingElement) FindIndex(data []byte) []int {
+	depth := 0
+	for _, tag := range elementTagRegex.FindAllSubmatchIndex(data, -1) {
+		if depth == 0 && len(bytes.TrimSpace(data[:tag[0]])) > 0 {
+			return nil
+		}
+		if string(data[tag[4]:tag[5]]) != string(name) {
+			if depth == 0 {
+				return nil
+			}
+			continue
+		}
+		switch {
+		case tag[7] > tag[6]:
+			// an empty element (e.g. <w:rPr/>) is complete at the outermost level
+			if depth == 0 {
+				return []int{0, tag[1]}
+			}
+		case tag[3] > tag[2]:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+			if depth == 0 {
+				return []int{0, tag[1]}
+			}
+		default:
+			depth++
+		}
+	}
+	return nil
+}
+
+// Find returns the element including the whitespace in front of it, or nil if the data does not start with it.
+func (name leadingElement) Find(data []byte) []byte {
+	if loc := name.FindIndex(data); loc != nil {
+		return data[loc[0]:loc[1]]
+	}
+	return nil
+}
+
+// lastOpenTag returns the offset behind the last open tag of the element with the given name, or -1.
+// Elements whose name only starts with the given name (e.g. <w:pPr> for <w:p>) are ignored.
+func lastOpenTag(data []byte, name string) int {
+	tag := []byte("<" + name)
+	for end := len(data); end > 0; {
+		start := bytes.LastIndex(data[:end], tag)
+		if start < 0 {
+			return -1
+		}
+		next := start + len(tag)
+		if next < len(data) && (data[next] == '>' || data[next] == ' ') {
+			if closing := bytes.IndexByte(data[next:], '>'); closing >= 0 {
+				return next + closing + 1
+			}
+			return -1
+		}
+		end = start
+	}
+	return -1
+}
@@ -0,0 +1,103 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestDocument_SetNewlineMode(t *testing.T) {
+	// only the newlines of the value are converted, the ones of the template are kept
+	body := `<w:p><w:pPr><w:pStyle w:val="Notes"/><w:sectPr><w:type w:val="nextPage"/></w:sectPr></w:pPr>` +
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Notes: {notes}</w:t></w:r><w:r><w:t>!` + "\n" + `</w:t></w:r></w:p>`
+	value := "first line\nsecond line\n\nnext paragraph"
+
+	tests := []struct {
+		name     string
+		mode     NewlineMode
+		expected string
+	}{
+		{
+			name: "keep",
+			mode: NewlineKeep,
+			expected: `<w:p><w:pPr><w:pStyle w:val="Notes"/><w:sectPr><w:type w:val="nextPage"/></w:sectPr></w:pPr>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Notes: ` + value + `</w:t></w:r>` +
+				`<w:r><w:t>!` + "\n" + `</w:t></w:r></w:p>`,
+		},
+		{
+			name: "break",
+			mode: NewlineBreak,
+			expected: `<w:p><w:pPr><w:pStyle w:val="Notes"/><w:sectPr><w:type w:val="nextPage"/></w:sectPr></w:pPr>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Notes: first line</w:t><w:br/>` +
+				`<w:t xml:space="preserve">second line</w:t><w:br/><w:t xml:space="preserve"></w:t><w:br/>` +
+				`<w:t xml:space="preserve">next paragraph</w:t></w:r><w:r><w:t>!` + "\n" + `</w:t></w:r></w:p>`,
+		},
+		{
+			name: "paragraph",
+			mode: NewlineParagraph,
+			expected: `<w:p><w:pPr><w:pStyle w:val="Notes"/><w:sectPr><w:type w:val="nextPage"/></w:sectPr></w:pPr>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Notes: first line</w:t><w:br/>` +
+				`<w:t xml:space="preserve">second line</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:pStyle w:val="Notes"/></w:pPr><w:r><w:rPr><w:b/></w:rPr>` +
+				`<w:t xml:space="preserve">next paragraph</w:t></w:r><w:r><w:t>!` + "\n" + `</w:t></w:r></w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, body)
+			doc.SetNewlineMode(tt.mode)
+			if err := doc.ReplaceAll(PlaceholderMap{"notes": value}); err != nil {
+				t.Fatal(err)
+			}
+			expected := newTestDocumentXml(tt.expected)
+			if written := string(writtenFile(t, doc, DocumentXml)); written != expected {
+				t.Errorf("unexpected document\nwant=%s\nhave=%s", expected, written)
+			}
+		})
+	}
+}
+
+func TestDocument_SetNewlineMode_NestedRun(t *testing.T) {
+	value := "first\n\nsecond"
+
+	for _, element := range []string{"w:hyperlink", "w:ins", "w:smartTag", "w:sdtContent"} {
+		t.Run(element, func(t *testing.T) {
+			body := `<w:p><w:pPr><w:pStyle w:val="Notes"/></w:pPr><` + element + `><w:r><w:rPr><w:b/></w:rPr>` +
+				`<w:t>{notes}</w:t></w:r></` + element + `></w:p>`
+			doc := openTestDocument(t, body)
+			doc.SetNewlineMode(NewlineParagraph)
+			if err := doc.ReplaceAll(PlaceholderMap{"notes": value}); err != nil {
+				t.Fatal(err)
+			}
+
+			// the paragraph cannot be split inside the element, a line break is used instead
+			expected := newTestDocumentXml(`<w:p><w:pPr><w:pStyle w:val="Notes"/></w:pPr><` + element + `>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">first</w:t><w:br/>` +
+				`<w:t xml:space="preserve">second</w:t></w:r></` + element + `></w:p>`)
+			if written := string(writtenFile(t, doc, DocumentXml)); written != expected {
+				t.Errorf("unexpected document\nwant=%s\nhave=%s", expected, written)
+			}
+			if text := writtenText(t, doc, DocumentXml); text != "firstsecond" {
+				t.Errorf("unexpected text, want=%s, have=%s", "firstsecond", text)
+			}
+		})
+	}
+}
+
+func TestDocument_SetNewlineMode_TrackedProperties(t *testing.T) {
+	// the previous properties of tracked formatting changes are nested inside the properties
+	paragraphProperties := `<w:pPr><w:jc w:val="center"/><w:pPrChange w:id="1" w:author="A"><w:pPr/></w:pPrChange></w:pPr>`
+	runProperties := `<w:rPr><w:b/><w:rPrChange w:id="2" w:author="A"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr>`
+	body := `<w:p>` + paragraphProperties + `<w:r>` + runProperties + `<w:t>{a}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	doc.SetNewlineMode(NewlineParagraph)
+	if err := doc.ReplaceAll(PlaceholderMap{"a": "one\n\ntwo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := newTestDocumentXml(`<w:p>` + paragraphProperties + `<w:r>` + runProperties +
+		`<w:t xml:space="preserve">one</w:t></w:r></w:p><w:p>` + paragraphProperties + `<w:r>` + runProperties +
+		`<w:t xml:space="preserve">two</w:t></w:r></w:p>`)
+	if written := string(writtenFile(t, doc, DocumentXml)); written != expected {
+		t.Errorf("unexpected document\nwant=%s\nhave=%s", expected, written)
+	}
+}
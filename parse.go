@@ -1,13 +1,14 @@
 package docx
 
 import (
+	"bytes"
 	"container/list"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"regexp"
+	"strings"
 )
 
 const (
@@ -39,13 +40,28 @@ type RunParser struct {
 	doc      []byte
 	runs     DocumentRuns
 	runStack list.List
+	config   ParserConfig
+	regexes  tagRegexes
+
+	// lastRunID is the ID of the last run created by the parser.
+	// IDs are scoped to the parser, which allows to parse multiple documents concurrently.
+	lastRunID int
 }
 
 // NewRunParser returns an initialized RunParser given the source-bytes.
+// The parser uses the DefaultParserConfig.
 func NewRunParser(doc []byte) *RunParser {
+	return NewRunParserWithConfig(doc, DefaultParserConfig)
+}
+
+// NewRunParserWithConfig returns an initialized RunParser which uses the given ParserConfig
+// to decide which elements are runs and text-runs.
+func NewRunParserWithConfig(doc []byte, config ParserConfig) *RunParser {
 	return &RunParser{
-		doc:  doc,
-		runs: DocumentRuns{},
+		doc:     doc,
+		runs:    DocumentRuns{},
+		config:  config,
+		regexes: config.tagRegexes(),
 	}
 }
 
@@ -63,7 +79,7 @@ func (parser *RunParser) Execute() error {
 		return err
 	}
 
-	return ValidatePositions(parser.doc, parser.runs)
+	return validatePositions(parser.doc, parser.runs, parser.regexes)
 }
 
 // Runs returns the all runs found by the parser.
@@ -71,6 +87,12 @@ func (parser *RunParser) Runs() DocumentRuns {
 	return parser.runs
 }
 
+// newRun returns a new, empty run which has only the next ID of the parser set.
+func (parser *RunParser) newRun() *Run {
+	parser.lastRunID += 1
+	return &Run{ID: parser.lastRunID}
+}
+
 // FindRuns will search through the document and return all runs found.
 // The text tags are not analyzed at this point, that'str the next step.
 func (parser *RunParser) findRuns() error {
@@ -78,7 +100,7 @@ func (parser *RunParser) findRuns() error {
 	docReader := NewReader(string(parser.doc))
 	decoder := xml.NewDecoder(docReader)
 
-	tmpRun := NewEmptyRun()
+	tmpRun := parser.newRun()
 	singleton := false
 
 	// nestCount holds the nesting-level. It is going to be incremented on every OpenTag and decremented
@@ -101,7 +123,7 @@ func (parser *RunParser) findRuns() error {
 		if nestCount > 0 {
 			tmpRun = popRun()
 		} else {
-			tmpRun = NewEmptyRun()
+			tmpRun = parser.newRun()
 		}
 		singleton = false
 	}
@@ -117,12 +139,12 @@ func (parser *RunParser) findRuns() error {
 
 		switch elem := tok.(type) {
 		case xml.StartElement:
-			if elem.Name.Local == RunElementName {
+			if parser.config.isRun(elem.Name) {
 
 				nestCount += 1
 				if nestCount > 1 {
 					parser.runStack.PushBack(tmpRun)
-					tmpRun = NewEmptyRun()
+					tmpRun = parser.newRun()
 				}
 
 				// tagEndPos points to '>' of the tag
@@ -138,13 +160,13 @@ func (parser *RunParser) findRuns() error {
 				// special case, a singleton tag: <w:r/> is also considered to be a start element
 				// since there is no real end tag, the element is marked for the EndElement case to handle it appropriately
 				tagStr := string(parser.doc[tagStartPos:tagEndPos])
-				if RunSingletonTagRegex.MatchString(tagStr) {
+				if parser.regexes.runSingleton.MatchString(tagStr) {
 					singleton = true
 				}
 			}
 
 		case xml.EndElement:
-			if elem.Name.Local == RunElementName {
+			if parser.config.isRun(elem.Name) {
 
 				// if the run is a singleton tag, it was already identified by the xml.StartElement case
 				// in that case, the CloseTag is the same as the openTag and no further work needs to be done
@@ -173,7 +195,7 @@ func (parser *RunParser) findRuns() error {
 	}
 
 	if nestCount != 0 {
-		log.Printf("invalid nestCount, should be 0 but is %d\n", nestCount)
+		logger.Printf("invalid nestCount, should be 0 but is %d\n", nestCount)
 		return ErrTagsInvalid
 	}
 
@@ -185,16 +207,28 @@ func (parser *RunParser) findTextRuns() error {
 	docReader := NewReader(string(parser.doc))
 	decoder := xml.NewDecoder(docReader)
 
-	// based on the current position, find out in which run we're at
+	// based on the current position, find out in which run we're at.
+	// The boundaries are inclusive, a text tag can never overlap the tags of its run anyway.
 	inRun := func(pos int64) *Run {
 		for _, run := range parser.runs {
-			if run.OpenTag.Start < pos && pos < run.CloseTag.End {
+			if run.OpenTag.Start <= pos && pos <= run.CloseTag.End {
 				return run
 			}
 		}
 		return nil
 	}
 
+	// text elements only constitute a text-run if they are a direct child of a run.
+	// Other elements with the same local name, e.g. the text of a DrawingML field (<a:fld><a:t>), are skipped.
+	var elements []xml.Name
+	inRunElement := func() bool {
+		return len(elements) > 0 && parser.config.isRun(elements[len(elements)-1])
+	}
+
+	// selfClosingText is set if the last text element was self-closing (<w:t/>).
+	// Such an element does not have any text and is thus skipped, including its EndElement which directly follows.
+	selfClosingText := false
+
 	for {
 		tok, err := decoder.Token()
 		if tok == nil || err == io.EOF {
@@ -206,13 +240,20 @@ func (parser *RunParser) findTextRuns() error {
 
 		switch elem := tok.(type) {
 		case xml.StartElement:
-			if elem.Name.Local == TextElementName {
+			isTextRun := parser.config.isText(elem.Name) && inRunElement()
+			elements = append(elements, elem.Name)
+			if isTextRun {
 
 				// tagEndPos points to '>' of the tag
 				tagEndPos := docReader.Pos()
 				// tagStartPos points to '<' of the tag
 				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
 
+				if bytes.HasSuffix(parser.doc[tagStartPos:tagEndPos], []byte("/>")) {
+					selfClosingText = true
+					break
+				}
+
 				currentRun := inRun(docReader.Pos())
 				if currentRun == nil {
 					return fmt.Errorf("unable to find currentRun for text start-element")
@@ -225,7 +266,14 @@ func (parser *RunParser) findTextRuns() error {
 			}
 
 		case xml.EndElement:
-			if elem.Name.Local == TextElementName {
+			if len(elements) > 0 {
+				elements = elements[:len(elements)-1]
+			}
+			if selfClosingText {
+				selfClosingText = false
+				break
+			}
+			if parser.config.isText(elem.Name) && inRunElement() {
 
 				// tagEndPos points to '>' of the tag
 				tagEndPos := docReader.Pos()
@@ -261,42 +309,70 @@ func (parser *RunParser) findOpenBracketPos(endBracketPos int64) int64 {
 // ValidatePositions will iterate over all runs and their texts (if any) and ensure that they match
 // their respective regex.
 // If the validation failed, the replacement will not work since offsets are wrong.
+// In that case a *ValidationError is returned which lists every tag which failed to validate.
+// The error wraps ErrTagsInvalid.
 func ValidatePositions(document []byte, runs []*Run) error {
-	parsingFailed := false
+	return validatePositions(document, runs, defaultTagRegexes)
+}
+
+// validatePositions validates the tag positions of all runs using the given regexes.
+func validatePositions(document []byte, runs []*Run, regexes tagRegexes) error {
+	validationErr := new(ValidationError)
+	validate := func(run *Run, tag string, position Position, regex *regexp.Regexp) {
+		if position.Match(regex, document) {
+			return
+		}
+		logger.Printf("%s failed to match %s", tag, run.String(document))
+		validationErr.Errors = append(validationErr.Errors, TagError{RunID: run.ID, Tag: tag})
+	}
+
 	for _, run := range runs {
 
 		// singleton tags must not be validated
-		if run.OpenTag.Match(RunSingletonTagRegex, document) {
+		if run.OpenTag.Match(regexes.runSingleton, document) {
 			continue
 		}
 
-		if !run.OpenTag.Match(RunOpenTagRegex, document) {
-			log.Println("RunOpenTagRegex failed to match", run.String(document))
-			parsingFailed = true
-		}
-		if !run.CloseTag.Match(RunCloseTagRegex, document) {
-			log.Println("RunCloseTagRegex failed to match", run.String(document))
-			parsingFailed = true
-		}
+		validate(run, "run open tag", run.OpenTag, regexes.runOpen)
+		validate(run, "run close tag", run.CloseTag, regexes.runClose)
 
 		if run.HasText {
-			if !run.Text.OpenTag.Match(TextOpenTagRegex, document) {
-				log.Println("TextOpenTagRegex failed to match", run.String(document))
-				parsingFailed = true
-			}
-			if !run.Text.CloseTag.Match(TextCloseTagRegex, document) {
-				log.Println("TextCloseTagRegex failed to match", run.String(document))
-				parsingFailed = true
-			}
+			validate(run, "text open tag", run.Text.OpenTag, regexes.textOpen)
+			validate(run, "text close tag", run.Text.CloseTag, regexes.textClose)
 		}
 	}
-	if parsingFailed {
-		return ErrTagsInvalid
+	if len(validationErr.Errors) > 0 {
+		return validationErr
 	}
 
 	return nil
 }
 
+// TagError describes a single tag of a run which failed to validate.
+type TagError struct {
+	RunID int    // RunID is the ID of the run the tag belongs to.
+	Tag   string // Tag describes the type of the tag, e.g. 'run open tag' or 'text close tag'.
+}
+
+// ValidationError is returned by ValidatePositions and collects all tags which failed to validate.
+type ValidationError struct {
+	Errors []TagError
+}
+
+// Error implements the error interface and lists all invalid tags.
+func (e *ValidationError) Error() string {
+	var failures []string
+	for _, tagErr := range e.Errors {
+		failures = append(failures, fmt.Sprintf("%s of run %d", tagErr.Tag, tagErr.RunID))
+	}
+	return fmt.Sprintf("%s: %s", ErrTagsInvalid, strings.Join(failures, ", "))
+}
+
+// Unwrap returns ErrTagsInvalid which allows to check for it using errors.Is().
+func (e *ValidationError) Unwrap() error {
+	return ErrTagsInvalid
+}
+
 // Position is a generic position of a tag, represented by byte offsets
 type Position struct {
 	Start int64
@@ -305,8 +381,20 @@ type Position struct {
 
 // Match will apply a MatchString using the given regex on the given data and returns true if the position
 // matches the regex inside the data.
+// Positions outside of the data never match.
 func (p Position) Match(regexp *regexp.Regexp, data []byte) bool {
-	return regexp.MatchString(string(data[p.Start:p.End]))
+	tag, ok := safeSlice(data, p.Start, p.End)
+	return ok && regexp.Match(tag)
+}
+
+// safeSlice returns b[start:end] if the offsets are within the bounds of b.
+// If they are not, e.g. because the offsets are corrupt or the data is only partially parsed, false is returned
+// instead of panicking.
+func safeSlice(b []byte, start, end int64) ([]byte, bool) {
+	if start < 0 || start > end || end > int64(len(b)) {
+		return nil, false
+	}
+	return b[start:end], true
 }
 
 // Valid returns true if Start <= End.
@@ -1,7 +1,11 @@
 package docx
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -95,3 +99,206 @@ func readFile(t testing.TB, path string) []byte {
 
 	return b
 }
+
+func TestValidatePositions(t *testing.T) {
+	prevLogger := logger
+	SetLogger(nil)
+	defer SetLogger(prevLogger)
+
+	docBytes := []byte(`<w:p><w:r><w:t>foo</w:t></w:r><w:r><w:t>bar</w:t></w:r></w:p>`)
+	parser := NewRunParser(docBytes)
+	if err := parser.Execute(); err != nil {
+		t.Errorf("parser.Execute failed: %s", err)
+		return
+	}
+
+	// corrupt the text close tag of the second run
+	runs := parser.Runs()
+	runs[1].Text.CloseTag.End -= 1
+
+	err := ValidatePositions(docBytes, runs)
+	if !errors.Is(err, ErrTagsInvalid) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrTagsInvalid, err)
+		return
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("unexpected error type, want=%T, have=%T", &ValidationError{}, err)
+		return
+	}
+	if len(validationErr.Errors) != 1 {
+		t.Errorf("unexpected amount of tag errors, want=1, have=%d", len(validationErr.Errors))
+		return
+	}
+	tagErr := validationErr.Errors[0]
+	if tagErr.RunID != runs[1].ID || tagErr.Tag != "text close tag" {
+		t.Errorf("unexpected tag error: %+v", tagErr)
+	}
+}
+
+func TestRunParser_WithConfig(t *testing.T) {
+	docBytes := []byte(`<p:sp><p:txBody><a:p><a:r><a:rPr lang="en-US"/><a:t>{foo}</a:t></a:r><a:r><a:t>bar</a:t></a:r><a:endParaRPr/></a:p></p:txBody></p:sp>`)
+	config := ParserConfig{
+		RunElements:  []xml.Name{{Space: "a", Local: "r"}},
+		TextElements: []xml.Name{{Space: "a", Local: "t"}},
+	}
+
+	parser := NewRunParserWithConfig(docBytes, config)
+	if err := parser.Execute(); err != nil {
+		t.Errorf("parser.Execute failed: %s", err)
+		return
+	}
+
+	runs := parser.Runs().WithText()
+	if len(runs) != 2 {
+		t.Errorf("unexpected amount of text-runs, want=2, have=%d", len(runs))
+		return
+	}
+	if text := runs[0].GetText(docBytes); text != "{foo}" {
+		t.Errorf("unexpected text, want={foo}, have=%s", text)
+	}
+
+	// a config for the WordprocessingML prefix must ignore the DrawingML runs
+	wordParser := NewRunParserWithConfig(docBytes, ParserConfig{
+		RunElements:  []xml.Name{{Space: "w", Local: "r"}},
+		TextElements: []xml.Name{{Space: "w", Local: "t"}},
+	})
+	if err := wordParser.Execute(); err != nil {
+		t.Errorf("parser.Execute failed: %s", err)
+		return
+	}
+	if len(wordParser.Runs()) != 0 {
+		t.Errorf("runs of other namespaces must be ignored, have=%d", len(wordParser.Runs()))
+	}
+}
+
+func TestRunParser_ScopedIDs(t *testing.T) {
+	docx := newTestDocx(t, nil)
+
+	// documents opened concurrently must not share the ID counters
+	docs := make([]*Document, 4)
+	errs := make(chan error, len(docs))
+	for i := range docs {
+		go func(i int) {
+			var err error
+			docs[i], err = OpenBytes(docx)
+			errs <- err
+		}(i)
+	}
+	for range docs {
+		if err := <-errs; err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	for _, doc := range docs {
+		runs := doc.runParsers[DocumentXml].Runs()
+		seen := make(map[int]bool)
+		for _, run := range runs {
+			if run.ID < 1 || run.ID > len(runs) || seen[run.ID] {
+				t.Errorf("unexpected run ID %d, expected unique IDs from 1 to %d", run.ID, len(runs))
+			}
+			seen[run.ID] = true
+		}
+
+		fragmentID := 0
+		for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+			for _, fragment := range placeholder.Fragments {
+				fragmentID++
+				if fragment.ID != fragmentID {
+					t.Errorf("unexpected fragment ID, want=%d, have=%d", fragmentID, fragment.ID)
+				}
+			}
+		}
+	}
+}
+
+func TestSafeSlice_CorruptPositions(t *testing.T) {
+	data := []byte(`<w:r><w:t>{foo}</w:t></w:r>`)
+	corrupt := &Run{
+		ID:      1,
+		HasText: true,
+		TagPair: TagPair{OpenTag: Position{Start: 0, End: 5}, CloseTag: Position{Start: 20, End: 99}},
+		Text:    TagPair{OpenTag: Position{Start: -3, End: 10}, CloseTag: Position{Start: 15, End: 9}},
+	}
+	fragment := &PlaceholderFragment{ID: 1, Run: corrupt, Position: Position{Start: 0, End: 500}}
+
+	// none of the diagnostics must panic
+	_ = corrupt.String(data)
+	_ = corrupt.GetText(data)
+	_ = fragment.String(data)
+	_ = PlaceholderFragment{ID: 2}.String(data)
+	_ = Placeholder{Fragments: []*PlaceholderFragment{fragment}}.Text(data)
+
+	if err := ValidatePositions(data, []*Run{corrupt}); !errors.Is(err, ErrTagsInvalid) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrTagsInvalid, err)
+	}
+
+	if b, ok := safeSlice(data, 5, 10); !ok || string(b) != "<w:t>" {
+		t.Errorf("unexpected bytes, want=%s, have=%s", "<w:t>", b)
+	}
+	for _, position := range []Position{{-1, 2}, {3, 2}, {0, int64(len(data) + 1)}} {
+		if _, ok := safeSlice(data, position.Start, position.End); ok {
+			t.Errorf("expected %v to be out of bounds", position)
+		}
+	}
+}
+
+func TestRunParser_TextOutsideOfRun(t *testing.T) {
+	// the text of a DrawingML field (<a:fld>) is not inside a run, it must neither fail the parser nor be assigned to a run
+	template := readFile(t, "./test/text_outside_run.xml")
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"client": "ACME", "date": "today"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Report for ACMEtoday"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+	if !bytes.Contains(writtenFile(t, doc, DocumentXml), []byte("<a:t>3/1/2024</a:t>")) {
+		t.Error("expected the field text to be kept")
+	}
+
+	// a text element which is not inside of any run is skipped as well
+	parser := NewRunParser([]byte(`<w:p><w:t>outside</w:t><w:r><w:t>inside</w:t></w:r></w:p>`))
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	runs := parser.Runs().WithText()
+	if len(runs) != 1 || runs[0].GetText(parser.doc) != "inside" {
+		t.Errorf("unexpected number of runs, want=%d, have=%d", 1, len(runs))
+	}
+}
+
+func TestRunParser_SelfClosingText(t *testing.T) {
+	// self-closing text elements do not have any text, they must neither fail the parser nor split placeholders
+	body := `<w:p><w:r><w:t>{fo</w:t></w:r><w:r><w:t/></w:r><w:r><w:t>o}</w:t></w:r>` +
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"/></w:r><w:r><w:t/><w:t>{bar}</w:t></w:r>` +
+		`<w:r><w:t>{baz}</w:t><w:t /></w:r></w:p>`
+	parser := NewRunParser([]byte(body))
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, run := range parser.Runs().WithText() {
+		texts = append(texts, run.GetText(parser.doc))
+	}
+	if expected := []string{"{fo", "o}", "{bar}", "{baz}"}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected texts, want=%v, have=%v", expected, texts)
+	}
+
+	doc := openTestDocument(t, body)
+	if err := doc.ReplaceAll(PlaceholderMap{"foo": "1", "bar": "2", "baz": "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "123" {
+		t.Errorf("unexpected text, want=%s, have=%s", "123", text)
+	}
+	if data := writtenFile(t, doc, DocumentXml); bytes.Count(data, []byte("/>")) != bytes.Count([]byte(body), []byte("/>")) {
+		t.Errorf("self-closing elements were not kept, have=%s", data)
+	}
+}
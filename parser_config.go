@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParserConfig configures which XML elements are considered to be runs and text-runs by the RunParser.
+// This allows to reuse the RunParser for other OOXML parts, e.g. DrawingML (<a:r> and <a:t>).
+//
+// The Space of an element name may either be the namespace URI or, if the namespace is not declared
+// inside the parsed bytes, the prefix. An empty Space matches the local name in any namespace.
+type ParserConfig struct {
+	// RunElements are the elements which constitute a run.
+	RunElements []xml.Name
+	// TextElements are the elements which constitute a text-run. Text-runs must be located inside runs.
+	TextElements []xml.Name
+}
+
+var (
+	// DefaultParserConfig is the WordprocessingML configuration which uses <w:r> and <w:t>.
+	DefaultParserConfig = ParserConfig{
+		RunElements:  []xml.Name{{Local: RunElementName}},
+		TextElements: []xml.Name{{Local: TextElementName}},
+	}
+
+	// defaultTagRegexes are used to validate the positions of the DefaultParserConfig
+	defaultTagRegexes = tagRegexes{
+		runOpen:      RunOpenTagRegex,
+		runClose:     RunCloseTagRegex,
+		runSingleton: RunSingletonTagRegex,
+		textOpen:     TextOpenTagRegex,
+		textClose:    TextCloseTagRegex,
+	}
+)
+
+// tagRegexes is the set of regular expressions which is used to validate the tag positions of runs.
+type tagRegexes struct {
+	runOpen      *regexp.Regexp
+	runClose     *regexp.Regexp
+	runSingleton *regexp.Regexp
+	textOpen     *regexp.Regexp
+	textClose    *regexp.Regexp
+}
+
+// isRun returns true if the given element name is configured as run element.
+func (c ParserConfig) isRun(name xml.Name) bool {
+	return matchesName(c.RunElements, name)
+}
+
+// isText returns true if the given element name is configured as text-run element.
+func (c ParserConfig) isText(name xml.Name) bool {
+	return matchesName(c.TextElements, name)
+}
+
+// isDefault returns true if the config equals the DefaultParserConfig.
+func (c ParserConfig) isDefault() bool {
+	return len(c.RunElements) == 1 && c.RunElements[0] == DefaultParserConfig.RunElements[0] &&
+		len(c.TextElements) == 1 && c.TextElements[0] == DefaultParserConfig.TextElements[0]
+}
+
+// tagRegexes returns the regular expressions to validate the tag positions of the configured elements.
+// Since the namespace prefixes are not known upfront, any prefix is accepted.
+func (c ParserConfig) tagRegexes() tagRegexes {
+	if c.isDefault() {
+		return defaultTagRegexes
+	}
+
+	localNames := func(names []xml.Name) string {
+		var locals []string
+		for _, name := range names {
+			locals = append(locals, regexp.QuoteMeta(name.Local))
+		}
+		return strings.Join(locals, "|")
+	}
+	run := localNames(c.RunElements)
+	text := localNames(c.TextElements)
+
+	return tagRegexes{
+		runOpen:      regexp.MustCompile(fmt.Sprintf(`^<([\w.-]+:)?(%s)(\s[^>]*)?>$`, run)),
+		runClose:     regexp.MustCompile(fmt.Sprintf(`^</([\w.-]+:)?(%s)\s*>$`, run)),
+		runSingleton: regexp.MustCompile(fmt.Sprintf(`^<([\w.-]+:)?(%s)(\s[^>]*)?/>$`, run)),
+		textOpen:     regexp.MustCompile(fmt.Sprintf(`^<([\w.-]+:)?(%s)(\s[^>]*)?>$`, text)),
+		textClose:    regexp.MustCompile(fmt.Sprintf(`^</([\w.-]+:)?(%s)\s*>$`, text)),
+	}
+}
+
+// matchesName checks whether the given name matches any of the names.
+func matchesName(names []xml.Name, name xml.Name) bool {
+	for _, n := range names {
+		if n.Local == name.Local && (n.Space == "" || n.Space == name.Space) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TextPartPathRegex matches all parts of the docx-archive which usually contain text: the document,
+// headers, footers, footnotes, endnotes and comments.
+var TextPartPathRegex = regexp.MustCompile(`^word/(document|header[0-9]*|footer[0-9]*|footnotes|endnotes|comments)\.xml$`)
+
+// DefaultPartFilter is the filter used by ReplaceAllParts if no filter is given, it matches TextPartPathRegex.
+func DefaultPartFilter(name string) bool {
+	return TextPartPathRegex.MatchString(name)
+}
+
+// ReplaceAllParts performs the replacement just like ReplaceAll, but in every XML part of the archive
+// which matches the partFilter instead of only the document, headers and footers.
+// If partFilter is nil, DefaultPartFilter is used.
+//
+// Parts which have not been parsed yet are parsed on demand and stay parsed afterwards,
+// parts without any runs are skipped.
+func (d *Document) ReplaceAllParts(placeholderMap PlaceholderMap, partFilter func(name string) bool) error {
+	if partFilter == nil {
+		partFilter = DefaultPartFilter
+	}
+
+	for _, name := range d.partNames() {
+		if !strings.HasSuffix(name, ".xml") || !partFilter(name) {
+			continue
+		}
+		parsed, err := d.parsePart(name)
+		if err != nil {
+			return err
+		}
+		if !parsed {
+			continue
+		}
+
+		changedBytes, err := d.replaceWithMissing(placeholderMap, name)
+		if err != nil {
+			return err
+		}
+		if err := d.SetFile(name, changedBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partNames returns the names of all parts of the archive, including the ones added through the Document API.
+func (d *Document) partNames() []string {
+	var names []string
+	known := make(map[string]bool)
+	for _, zipFile := range d.zipFile.File {
+		if !known[zipFile.Name] {
+			known[zipFile.Name] = true
+			names = append(names, zipFile.Name)
+		}
+	}
+	for _, added := range []FileMap{d.files, d.rawFiles} {
+		for name := range added {
+			if !known[name] {
+				known[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// parsePart ensures that the part is parsed, it returns false if the part does not contain any runs.
+// Once parsed, the part is written from the parsed files instead of the raw files.
+func (d *Document) parsePart(name string) (bool, error) {
+	if _, exists := d.files[name]; exists {
+		return true, nil
+	}
+
+	data, err := d.readRawFile(name)
+	if err != nil {
+		return false, err
+	}
+	// cheap check to avoid parsing parts like styles or settings
+	if !bytes.Contains(data, []byte("<w:r>")) && !bytes.Contains(data, []byte("<w:r ")) {
+		return false, nil
+	}
+
+	d.files[name] = d.preprocess(name, data)
+	if err := d.parseFile(name); err != nil {
+		delete(d.files, name)
+		delete(d.runParsers, name)
+		delete(d.filePlaceholders, name)
+		delete(d.fileReplacers, name)
+		return false, fmt.Errorf("unable to parse %s: %s", name, err)
+	}
+	delete(d.rawFiles, name)
+	return true, nil
+}
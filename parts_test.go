@@ -0,0 +1,65 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceAllParts(t *testing.T) {
+	footnotes := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:footnote w:id="1"><w:p><w:r><w:t>Source: {source}</w:t></w:r></w:p></w:footnote></w:footnotes>`
+	comments := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:comment w:id="0"><w:p><w:r><w:t>Ask {source}</w:t></w:r></w:p></w:comment></w:comments>`
+	styles := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:styles>`
+
+	open := func(t *testing.T) *Document {
+		doc, err := OpenBytes(newTestDocx(t, map[string]string{
+			DocumentXml:          newTestDocumentXml(`<w:p><w:r><w:t>{source}</w:t></w:r></w:p>`),
+			"word/footnotes.xml": footnotes,
+			"word/comments.xml":  comments,
+			"word/styles.xml":    styles,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+
+	t.Run("default filter", func(t *testing.T) {
+		doc := open(t)
+		if err := doc.ReplaceAllParts(PlaceholderMap{"source": "Wikipedia"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		for file, expected := range map[string]string{
+			DocumentXml:          "Wikipedia",
+			"word/footnotes.xml": "Source: Wikipedia",
+			"word/comments.xml":  "Ask Wikipedia",
+		} {
+			if text := writtenText(t, doc, file); text != expected {
+				t.Errorf("unexpected text of %s, want=%s, have=%s", file, expected, text)
+			}
+		}
+		if written := string(writtenFile(t, doc, "word/styles.xml")); written != styles {
+			t.Error("parts without runs must not be modified")
+		}
+	})
+
+	t.Run("custom filter", func(t *testing.T) {
+		doc := open(t)
+		filter := func(name string) bool {
+			return name == DocumentXml || strings.HasSuffix(name, "footnotes.xml")
+		}
+		if err := doc.ReplaceAllParts(PlaceholderMap{"source": "Wikipedia"}, filter); err != nil {
+			t.Fatal(err)
+		}
+		if text := writtenText(t, doc, "word/footnotes.xml"); text != "Source: Wikipedia" {
+			t.Errorf("footnote was not replaced, have=%s", text)
+		}
+		if text := writtenText(t, doc, "word/comments.xml"); text != "Ask {source}" {
+			t.Errorf("comment was changed, have=%s", text)
+		}
+	})
+}
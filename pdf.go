@@ -0,0 +1,92 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ExecInput is replaced with the path of the docx file inside the arguments of an ExecConverter.
+	ExecInput = "%input%"
+	// ExecOutputDir is replaced with the directory in which the ExecConverter expects the PDF.
+	ExecOutputDir = "%outdir%"
+)
+
+// Converter converts a docx document into a PDF. The library does not bundle a PDF engine,
+// converters wrap external tools or services like LibreOffice or Gotenberg instead.
+type Converter interface {
+	Convert(docx []byte) ([]byte, error)
+}
+
+// ConverterFunc allows to use an ordinary function as Converter.
+type ConverterFunc func(docx []byte) ([]byte, error)
+
+// Convert calls f(docx).
+func (f ConverterFunc) Convert(docx []byte) ([]byte, error) {
+	return f(docx)
+}
+
+// RenderPDF writes the document and converts it into a PDF using the given converter.
+func (d *Document) RenderPDF(conv Converter) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := d.Write(buf); err != nil {
+		return nil, err
+	}
+	pdf, err := conv.Convert(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert document to pdf: %w", err)
+	}
+	return pdf, nil
+}
+
+// ExecConverter converts documents by executing a command. The docx is written into a temporary directory
+// as 'document.docx', the command is expected to create 'document.pdf' inside the output directory.
+// The arguments may contain ExecInput and ExecOutputDir which are replaced with the respective paths.
+type ExecConverter struct {
+	Command string
+	Args    []string
+}
+
+// NewLibreOfficeConverter returns an ExecConverter which uses a headless LibreOffice ('soffice') for the conversion.
+func NewLibreOfficeConverter() *ExecConverter {
+	return &ExecConverter{
+		Command: "soffice",
+		Args:    []string{"--headless", "--convert-to", "pdf", "--outdir", ExecOutputDir, ExecInput},
+	}
+}
+
+// Convert implements the Converter interface.
+func (c *ExecConverter) Convert(docx []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "docx-convert-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "document.docx")
+	if err := os.WriteFile(input, docx, 0600); err != nil {
+		return nil, fmt.Errorf("unable to write %s: %s", input, err)
+	}
+
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		arg = strings.ReplaceAll(arg, ExecInput, input)
+		args[i] = strings.ReplaceAll(arg, ExecOutputDir, dir)
+	}
+
+	cmd := exec.Command(c.Command, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %s: %s", c.Command, err, bytes.TrimSpace(output))
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(dir, "document.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("%s did not create a pdf: %s", c.Command, err)
+	}
+	return pdf, nil
+}
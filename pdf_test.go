@@ -0,0 +1,63 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestDocument_RenderPDF(t *testing.T) {
+	doc, err := Open("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	var converted []byte
+	pdf, err := doc.RenderPDF(ConverterFunc(func(docx []byte) ([]byte, error) {
+		converted = docx
+		return []byte("%PDF-1.7"), nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pdf) != "%PDF-1.7" {
+		t.Errorf("unexpected output, have=%s", pdf)
+	}
+	if _, err := OpenBytes(converted); err != nil {
+		t.Error("expected the converter to receive a valid docx", err)
+	}
+
+	failure := errors.New("conversion failed")
+	_, err = doc.RenderPDF(ConverterFunc(func(docx []byte) ([]byte, error) {
+		return nil, failure
+	}))
+	if !errors.Is(err, failure) {
+		t.Errorf("converter error was not returned, have=%v", err)
+	}
+}
+
+func TestExecConverter(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	// the 'conversion' copies the input, which allows to verify the substituted paths
+	converter := &ExecConverter{
+		Command: "sh",
+		Args:    []string{"-c", `cp "$1" "$2/document.pdf"`, "sh", ExecInput, ExecOutputDir},
+	}
+	pdf, err := converter.Convert([]byte("docx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pdf, []byte("docx")) {
+		t.Errorf("unexpected output, want=%s, have=%s", "docx", pdf)
+	}
+
+	converter.Args = []string{"-c", "echo broken >&2; exit 1"}
+	if _, err := converter.Convert([]byte("docx")); err == nil {
+		t.Error("expected a failing command to return an error")
+	}
+}
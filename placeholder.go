@@ -2,23 +2,26 @@ package docx
 
 import (
 	"fmt"
-	"log"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
-	// OpenDelimiter defines the opening delimiter for the placeholders used inside a docx-document.
+	// OpenDelimiter defines the default opening delimiter for the placeholders used inside a docx-document.
+	// Documents may use other delimiters, see Document.SetDelimiters.
 	OpenDelimiter rune = '{'
-	// CloseDelimiter defines the closing delimiter for the placeholders used inside a docx-document.
+	// CloseDelimiter defines the default closing delimiter for the placeholders used inside a docx-document.
+	// Documents may use other delimiters, see Document.SetDelimiters.
 	CloseDelimiter rune = '}'
 )
 
 var (
 	// OpenDelimiterRegex is used to quickly match the opening delimiter and find it'str positions.
-	OpenDelimiterRegex = regexp.MustCompile(string(OpenDelimiter))
+	OpenDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(OpenDelimiter)))
 	// CloseDelimiterRegex is used to quickly match the closing delimiter and find it'str positions.
-	CloseDelimiterRegex = regexp.MustCompile(string(CloseDelimiter))
+	CloseDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(CloseDelimiter)))
 )
 
 // PlaceholderMap is the type used to map the placeholder keys (without delimiters) to the replacement values
@@ -29,44 +32,151 @@ type PlaceholderMap map[string]interface{}
 // byte-offsets of the fragment inside the underlying byte-data.
 type Placeholder struct {
 	Fragments []*PlaceholderFragment
+
+	// replaced is set once the placeholder was replaced by a Replacer
+	replaced bool
 }
 
 // Text assembles the placeholder fragments using the given docBytes and returns the full placeholder literal.
+// Fragments without a run or with offsets outside of docBytes are skipped instead of causing a panic.
 func (p Placeholder) Text(docBytes []byte) string {
 	str := ""
 	for _, fragment := range p.Fragments {
+		if fragment == nil || fragment.Run == nil {
+			continue
+		}
 		s := fragment.Run.Text.OpenTag.End
-		t := docBytes[s+fragment.Position.Start : s+fragment.Position.End]
-		str += string(t)
+		if text, ok := safeSlice(docBytes, s+fragment.Position.Start, s+fragment.Position.End); ok {
+			str += string(text)
+		}
 	}
 	return str
 }
 
+// Replaced returns true if the placeholder was replaced by a Replacer.
+// The text and the positions of a replaced placeholder refer to the inserted value instead of the placeholder.
+func (p Placeholder) Replaced() bool {
+	return p.replaced
+}
+
 // StartPos returns the absolute start position of the placeholder.
+// If the placeholder has no fragments or the first fragment has no run, 0 is returned.
+// Once the placeholder is replaced (see Replaced), it is the start position of the inserted value.
 func (p Placeholder) StartPos() int64 {
+	if len(p.Fragments) == 0 || p.Fragments[0] == nil || p.Fragments[0].Run == nil {
+		return 0
+	}
 	return p.Fragments[0].Run.Text.OpenTag.End + p.Fragments[0].Position.Start
 }
 
 // EndPos returns the absolute end position of the placeholder.
+// If the placeholder has no fragments or the last fragment has no run, 0 is returned.
+// Once the placeholder is replaced (see Replaced), the fragments behind the first one are empty and the
+// position is no longer meaningful, use the EndPos of the first fragment instead.
 func (p Placeholder) EndPos() int64 {
 	end := len(p.Fragments) - 1
+	if end < 0 || p.Fragments[end] == nil || p.Fragments[end].Run == nil {
+		return 0
+	}
 	return p.Fragments[end].Run.Text.OpenTag.End + p.Fragments[end].Position.End
 }
 
+// RunIDs returns the IDs of the runs in which the fragments of the placeholder reside, in fragment order.
+// Fragments without a run are skipped.
+func (p Placeholder) RunIDs() []int {
+	var ids []int
+	for _, fragment := range p.Fragments {
+		if fragment == nil || fragment.Run == nil {
+			continue
+		}
+		ids = append(ids, fragment.Run.ID)
+	}
+	return ids
+}
+
+// FileOffsets returns the absolute byte positions of the fragments inside the given docBytes, in fragment order.
+// docBytes[offset.Start:offset.End] is the text of the fragment. Just like in Text, fragments without a run
+// or with offsets outside of docBytes are skipped.
+func (p Placeholder) FileOffsets(docBytes []byte) []Position {
+	var offsets []Position
+	for _, fragment := range p.Fragments {
+		if fragment == nil || fragment.Run == nil {
+			continue
+		}
+		s := fragment.Run.Text.OpenTag.End
+		start, end := s+fragment.Position.Start, s+fragment.Position.End
+		if _, ok := safeSlice(docBytes, start, end); ok {
+			offsets = append(offsets, Position{Start: start, End: end})
+		}
+	}
+	return offsets
+}
+
 // Valid determines whether the placeholder can be used.
-// A placeholder is considered valid, if all fragments are valid.
+// A placeholder is considered valid, if it has fragments and all of them are valid.
 func (p Placeholder) Valid() bool {
+	if len(p.Fragments) == 0 {
+		return false
+	}
 	for _, fragment := range p.Fragments {
-		if !fragment.Valid() {
+		if fragment == nil || fragment.Run == nil || !fragment.Valid() {
 			return false
 		}
 	}
 	return true
 }
 
+// SkipReason describes why the parser skipped a placeholder.
+type SkipReason string
+
+const (
+	// SkipNested is reported for runs with nested placeholders like '{foo{bar}}', which are not supported.
+	SkipNested SkipReason = "nested"
+	// SkipUnclosed is reported for a placeholder which is still open at the end of the runs, e.g. '{foo'.
+	SkipUnclosed SkipReason = "unclosed"
+	// SkipInvalid is reported for placeholders with invalid positions or without both delimiters.
+	SkipInvalid SkipReason = "invalid"
+)
+
+// SkippedPlaceholder is a placeholder which the parser detected, but skipped.
+type SkippedPlaceholder struct {
+	Reason SkipReason
+	// RunID is the ID of the run in which the skipped placeholder starts.
+	RunID int
+	// Text is the text of the placeholder, for nested placeholders it is the text of the whole run.
+	Text string
+}
+
+// ParseResult holds the valid placeholders as well as the ones the parser skipped.
+type ParseResult struct {
+	Placeholders []*Placeholder
+	Skipped      []SkippedPlaceholder
+}
+
 // ParsePlaceholders will, given the document run positions and the bytes, parse out all placeholders including
-// their fragments.
+// their fragments. The placeholders are delimited by OpenDelimiter and CloseDelimiter.
 func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Placeholder, err error) {
+	result, err := parsePlaceholders(runs, docBytes, defaultDelimiters)
+	if err != nil {
+		return nil, err
+	}
+	return result.Placeholders, nil
+}
+
+// ParsePlaceholdersDetailed parses the placeholders just like ParsePlaceholders, but additionally returns the
+// placeholders which were skipped (e.g. nested ones) instead of only logging them. This gives full visibility into
+// what the parser did.
+func ParsePlaceholdersDetailed(runs DocumentRuns, docBytes []byte) (result ParseResult, err error) {
+	return parsePlaceholders(runs, docBytes, defaultDelimiters)
+}
+
+// parsePlaceholders parses the placeholders which are enclosed by the given delimiters.
+func parsePlaceholders(runs DocumentRuns, docBytes []byte, delims delimiters) (result ParseResult, err error) {
+	var placeholders []*Placeholder
+	skip := func(reason SkipReason, runID int, text string) {
+		result.Skipped = append(result.Skipped, SkippedPlaceholder{Reason: reason, RunID: runID, Text: text})
+	}
+
 	// tmp vars used to preserve state across iterations
 	unclosedPlaceholder := new(Placeholder)
 	hasOpenPlaceholder := false
@@ -74,23 +184,8 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 	for _, run := range runs.WithText() {
 		runText := run.GetText(docBytes)
 
-		openDelimPositions := OpenDelimiterRegex.FindAllStringIndex(runText, -1)
-		closeDelimPositions := CloseDelimiterRegex.FindAllStringIndex(runText, -1)
-
-		// FindAllStringIndex returns a [][]int whereas the nested []int has only 2 keys (0 and 1)
-		// We're only interested in the first key as that one indicates the position of the delimiter
-		delimPositions := func(positions [][]int) []int {
-			var pos []int
-			for _, position := range positions {
-				pos = append(pos, position[0])
-			}
-			return pos
-		}
-
-		// index all delimiters
-		openPos := delimPositions(openDelimPositions)
-		closePos := delimPositions(closeDelimPositions)
-
+		// index all delimiters, escaped delimiters (e.g. '{{') are literals and thus ignored
+		openPos, closePos := delimiterPositions(runText, hasOpenPlaceholder, delims)
 
 		// In case there are the same amount of open and close delimiters.
 		// Here we will have three three different sub-cases.
@@ -113,9 +208,8 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 			// isSpecialCase checks if, for all found delimiters, startPos > endPos is true (case 2)
 			isSpecialCase := func() bool {
 				for i := 0; i < len(openPos); i++ {
-					start := openPos[i]
-					end := closePos[i] + 1 // +1 is required to include the closing delimiter in the text
-					if start > end {
+					// an open delimiter directly behind the close delimiter (e.g. '}{foo}{') starts behind it as well
+					if openPos[i] > closePos[i] {
 						return true
 					}
 				}
@@ -141,7 +235,7 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 				// handle the easy part (everything between the the culprit first '}' and last '{' in the example of '}foo{bar}foo{'
 				validOpenPos := openPos[:len(openPos)-1]
 				validClosePos := closePos[1:]
-				placeholders = append(placeholders, assembleFullPlaceholders(run, validOpenPos, validClosePos)...)
+				placeholders = append(placeholders, assembleFullPlaceholders(run, validOpenPos, validClosePos, delims)...)
 
 				// extract the first open and last close delimiter positions as they are the one causing issues.
 				lastOpenPos := openPos[len(openPos)-1]
@@ -149,16 +243,16 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 
 				// we MUST be having an unclosedPlaceholder or the user made a typo like double-closing ('{foo}}{bar')
 				if !hasOpenPlaceholder {
-					return nil, fmt.Errorf("unexpected %c in run %d \"%s\"), missing preceeding %c", CloseDelimiter, run.ID, run.GetText(docBytes), OpenDelimiter)
+					return ParseResult{}, fmt.Errorf("unexpected %c in run %d \"%s\"), missing preceeding %c", delims.close, run.ID, run.GetText(docBytes), delims.open)
 				}
 
 				// everything up to firstClosePos belongs to the currently open placeholder
-				fragment := NewPlaceholderFragment(0, Position{0, int64(firstClosePos)+1}, run)
+				fragment := newFragment(Position{0, int64(firstClosePos + utf8.RuneLen(delims.close))}, run)
 				unclosedPlaceholder.Fragments = append(unclosedPlaceholder.Fragments, fragment)
 				placeholders = append(placeholders, unclosedPlaceholder)
 
 				// a new, unclosed, placeholder starts at lastOpenPos
-				fragment = NewPlaceholderFragment(0, Position{int64(lastOpenPos), int64(len(runText))}, run)
+				fragment = newFragment(Position{int64(lastOpenPos), int64(len(runText))}, run)
 				unclosedPlaceholder = new(Placeholder)
 				unclosedPlaceholder.Fragments = append(unclosedPlaceholder.Fragments, fragment)
 				hasOpenPlaceholder = true
@@ -171,12 +265,13 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 			//	- cut out
 			// 	- skip the run (that's what we do because we're lazy bums)
 			if isNestedCase() {
-				log.Printf("detected nested placeholder in run %d \"%s\", skipping \n", run.ID, run.GetText(docBytes))
+				logger.Printf("detected nested placeholder in run %d \"%s\", skipping \n", run.ID, run.GetText(docBytes))
+				skip(SkipNested, run.ID, run.GetText(docBytes))
 				continue
 			}
 
 			// case 1, assemble and continue
-			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos, closePos)...)
+			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos, closePos, delims)...)
 			continue
 		}
 
@@ -187,12 +282,18 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 		// unclosed placeholder.
 		if len(openPos) > len(closePos) {
 			// merge full placeholders in the run, leaving out the last openPos since
-			// we know that the one is left over and must be handled separately below
-			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos[:len(openPos)-1], closePos)...)
+			// we know that the one is left over and must be handled separately below.
+			// If there are even more open delimiters (e.g. '{foo{bar'), they cannot be paired and are skipped.
+			if fullOpenPos := openPos[:len(openPos)-1]; len(fullOpenPos) == len(closePos) {
+				placeholders = append(placeholders, assembleFullPlaceholders(run, fullOpenPos, closePos, delims)...)
+			} else {
+				logger.Printf("detected nested placeholder in run %d \"%s\", skipping \n", run.ID, runText)
+				skip(SkipNested, run.ID, runText)
+			}
 
 			// add the unclosed part of the placeholder to a tmp placeholder var
 			unclosedOpenPos := openPos[len(openPos)-1]
-			fragment := NewPlaceholderFragment(0, Position{int64(unclosedOpenPos), int64(len(runText))}, run)
+			fragment := newFragment(Position{int64(unclosedOpenPos), int64(len(runText))}, run)
 			unclosedPlaceholder.Fragments = append(unclosedPlaceholder.Fragments, fragment)
 			hasOpenPlaceholder = true
 			continue
@@ -202,19 +303,22 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 		// this can only mean that there must be an unclosed placeholder which
 		// is closed in this run.
 		if len(openPos) < len(closePos) {
-			// merge full placeholders in the run, leaving out the last closePos since
-			// we know that the one is left over and must be handled separately below
-			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos, closePos[:len(closePos)-1])...)
-
-			// there is only a closePos and no open pos
-			if len(closePos) == 1 {
-				fragment := NewPlaceholderFragment(0, Position{0, int64(int64(closePos[0]) + 1)}, run)
+			// the first closePos closes the unclosed placeholder
+			remainingClosePos := closePos
+			if hasOpenPlaceholder {
+				fragment := newFragment(Position{0, int64(closePos[0] + utf8.RuneLen(delims.close))}, run)
 				unclosedPlaceholder.Fragments = append(unclosedPlaceholder.Fragments, fragment)
 				placeholders = append(placeholders, unclosedPlaceholder)
 				unclosedPlaceholder = new(Placeholder)
 				hasOpenPlaceholder = false
-				continue
+				remainingClosePos = closePos[1:]
 			}
+
+			// merge full placeholders in the run, leaving out the first closePos if it was handled above.
+			// Pairing the delimiters otherwise (e.g. 'eting} and {name}') would result in broken fragments.
+			// Further stray close delimiters (e.g. 'eting} and} {name}') are skipped while pairing.
+			pairedOpenPos, pairedClosePos := pairDelimiters(openPos, remainingClosePos)
+			placeholders = append(placeholders, assembleFullPlaceholders(run, pairedOpenPos, pairedClosePos, delims)...)
 			continue
 		}
 
@@ -227,33 +331,70 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 		//	3) '-baz}
 		if len(openPos) == 0 && len(closePos) == 0 {
 			if hasOpenPlaceholder {
-				fragment := NewPlaceholderFragment(0, Position{0, int64(len(runText))}, run)
+				fragment := newFragment(Position{0, int64(len(runText))}, run)
 				unclosedPlaceholder.Fragments = append(unclosedPlaceholder.Fragments, fragment)
 				continue
 			}
 		}
 	}
 
+	if hasOpenPlaceholder {
+		skip(SkipUnclosed, placeholderRunID(unclosedPlaceholder), unclosedPlaceholder.Text(docBytes))
+	}
+
 	// Make sure that we're dealing with valid and proper placeholders only.
 	// Everything else may cause issues like out of bounds errors or any other sort of weird things.
 	// Here we will also assemble the final list of placeholders and return only the valid ones.
 	var validPlaceholders []*Placeholder
 	for _, placeholder := range placeholders {
 		if !placeholder.Valid() {
+			skip(SkipInvalid, placeholderRunID(placeholder), placeholder.Text(docBytes))
 			continue
 		}
 
 		// in order to catch false positives, ensure that all placeholders have BOTH delimiters
 		text := placeholder.Text(docBytes)
-		if !strings.ContainsRune(text, OpenDelimiter) ||
-			!strings.ContainsRune(text, CloseDelimiter) {
+		if !strings.ContainsRune(text, delims.open) ||
+			!strings.ContainsRune(text, delims.close) {
+			skip(SkipInvalid, placeholderRunID(placeholder), text)
+			continue
+		}
+
+		// empty placeholders spanning multiple runs are literals, just like the ones inside a single run
+		if text == delims.String() {
 			continue
 		}
 
 		// placeholder is valid
 		validPlaceholders = append(validPlaceholders, placeholder)
 	}
-	return validPlaceholders, nil
+
+	// fragment IDs are scoped to the parsed placeholders, which allows to parse multiple documents concurrently
+	fragmentID := 0
+	for _, placeholder := range validPlaceholders {
+		for _, fragment := range placeholder.Fragments {
+			fragmentID += 1
+			fragment.ID = fragmentID
+		}
+	}
+	result.Placeholders = validPlaceholders
+	return result, nil
+}
+
+// placeholderRunID returns the ID of the run in which the placeholder starts, or 0 if it is unknown.
+func placeholderRunID(placeholder *Placeholder) int {
+	if len(placeholder.Fragments) == 0 || placeholder.Fragments[0] == nil || placeholder.Fragments[0].Run == nil {
+		return 0
+	}
+	return placeholder.Fragments[0].Run.ID
+}
+
+// newFragment returns a PlaceholderFragment without an ID, it is set once all placeholders are parsed.
+func newFragment(pos Position, run *Run) *PlaceholderFragment {
+	return &PlaceholderFragment{
+		Position: pos,
+		Run:      run,
+	}
 }
 
 // assembleFullPlaceholders will extract all complete placeholders inside the run given a open and close position.
@@ -261,46 +402,108 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 // openPos and closePos are expected to be symmetrical (e.g. same length).
 // Example: openPos := []int{10,20,30}; closePos := []int{13, 23, 33} resulting in 3 fragments (10,13),(20,23),(30,33)
 // The n-th elements inside openPos and closePos must be matching delimiter positions.
-func assembleFullPlaceholders(run *Run, openPos, closePos []int) (placeholders []*Placeholder) {
+func assembleFullPlaceholders(run *Run, openPos, closePos []int, delims delimiters) (placeholders []*Placeholder) {
 	for i := 0; i < len(openPos); i++ {
 		start := openPos[i]
-		end := closePos[i] + 1 // +1 is required to include the closing delimiter in the text
-		fragment := NewPlaceholderFragment(0, Position{int64(start), int64(end)}, run)
+		end := closePos[i] + utf8.RuneLen(delims.close) // the closing delimiter is included in the text, it may be multi-byte
+		fragment := newFragment(Position{int64(start), int64(end)}, run)
 		p := &Placeholder{Fragments: []*PlaceholderFragment{fragment}}
 		placeholders = append(placeholders, p)
 	}
 	return placeholders
 }
 
+// pairDelimiters pairs every open delimiter with the first close delimiter behind it.
+// Close delimiters in front of an open delimiter are skipped, as well as open delimiters without a close delimiter.
+func pairDelimiters(openPos, closePos []int) (pairedOpenPos, pairedClosePos []int) {
+	j := 0
+	for _, open := range openPos {
+		for j < len(closePos) && closePos[j] < open {
+			j++
+		}
+		if j == len(closePos) {
+			break
+		}
+		pairedOpenPos = append(pairedOpenPos, open)
+		pairedClosePos = append(pairedClosePos, closePos[j])
+		j++
+	}
+	return pairedOpenPos, pairedClosePos
+}
+
+// delimiterPositions returns the byte positions of all open and close delimiters inside the given text.
+// Delimiters can be escaped by doubling them (e.g. '{{' or '}}'), escaped delimiters are literals and not returned.
+// Inside a placeholder the first close delimiter always closes it, thus '{{{foo}}}' is read as
+// literal '{', placeholder '{foo}' and literal '}'.
+// Delimiters which form a grapheme cluster with the following rune (see extendsGrapheme) are literals as well,
+// just like empty placeholders ('{}'). If such a close delimiter ends a placeholder of the same text, its open
+// delimiter is a literal too, thus '{a}\u0301' is literal text as a whole.
+// The inPlaceholder flag indicates whether the text starts inside an unclosed placeholder of a previous run.
+func delimiterPositions(text string, inPlaceholder bool, delims delimiters) (openPos, closePos []int) {
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		next, nextSize := utf8.DecodeRuneInString(text[i+size:])
+
+		switch {
+		case (r == delims.open || r == delims.close) && extendsGrapheme(next):
+			// the delimiter is the base of a grapheme cluster (e.g. '}' followed by a combining mark),
+			// replacing it would attach the mark to the value, thus it is a literal.
+			// The open delimiter of the placeholder it would close must not be paired with a later close delimiter.
+			if r == delims.close && inPlaceholder && len(openPos) > 0 && (len(closePos) == 0 || closePos[len(closePos)-1] < openPos[len(openPos)-1]) {
+				openPos = openPos[:len(openPos)-1]
+				inPlaceholder = false
+			}
+		case r == delims.open && next == delims.close:
+			// an empty placeholder (e.g. '{}' in prose) has no key, thus it is a literal as well
+			i += size + nextSize
+			continue
+		case r == delims.open && !inPlaceholder && next == delims.open:
+			i += size + nextSize
+			continue
+		case r == delims.open:
+			openPos = append(openPos, i)
+			inPlaceholder = true
+		case r == delims.close && !inPlaceholder && next == delims.close:
+			i += size + nextSize
+			continue
+		case r == delims.close:
+			closePos = append(closePos, i)
+			inPlaceholder = false
+		}
+		i += size
+	}
+	return openPos, closePos
+}
+
+// extendsGrapheme returns true if the rune is part of the grapheme cluster of the preceding rune.
+// These are combining marks (e.g. U+0301), the zero-width joiner and variation selectors.
+// Runes in the following run are not known while parsing, thus only runes inside the same run are considered.
+func extendsGrapheme(r rune) bool {
+	return r == '\u200d' || unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector)
+}
+
 // AddPlaceholderDelimiter will wrap the given string with OpenDelimiter and CloseDelimiter.
 // If the given string is already a delimited placeholder, it is returned unchanged.
 func AddPlaceholderDelimiter(s string) string {
-	if IsDelimitedPlaceholder(s) {
-		return s
-	}
-	return fmt.Sprintf("%c%s%c", OpenDelimiter, s, CloseDelimiter)
+	return defaultDelimiters.add(s)
 }
 
 // RemovePlaceholderDelimiter removes OpenDelimiter and CloseDelimiter from the given text.
 // If the given text is not a delimited placeholder, it is returned unchanged.
 func RemovePlaceholderDelimiter(s string) string {
-	if !IsDelimitedPlaceholder(s) {
-		return s
-	}
-	return strings.Trim(s, fmt.Sprintf("%s%s", string(OpenDelimiter), string(CloseDelimiter)))
+	return defaultDelimiters.remove(s)
+}
+
+// NormalizePlaceholder removes zero-width characters from the placeholder, trims all whitespace
+// (including non-breaking spaces) between the delimiters and collapses inner whitespace into a single space.
+// E.g. '{ foo\u00a0 bar\u200b }' becomes '{foo bar}'. Placeholders without delimiters are normalized as well.
+func NormalizePlaceholder(s string) string {
+	return defaultDelimiters.normalize(s)
 }
 
 // IsDelimitedPlaceholder returns true if the given string is a delimited placeholder.
 // It checks whether the first and last rune in the string is the OpenDelimiter and CloseDelimiter respectively.
 // If the string is empty, false is returned.
 func IsDelimitedPlaceholder(s string) bool {
-	if len(s) < 1 {
-		return false
-	}
-	first := s[0]
-	last := s[len(s)-1]
-	if rune(first) == OpenDelimiter && rune(last) == CloseDelimiter {
-		return true
-	}
-	return false
+	return defaultDelimiters.isDelimited(s)
 }
@@ -1,9 +1,12 @@
 package docx
 
-import "fmt"
+import (
+	"fmt"
+	"sync/atomic"
+)
 
 var (
-	fragmentId = 0 // global fragment id counter, incremented on NewPlaceholderFragment
+	fragmentId int64 = 0 // global fragment id counter, incremented on NewPlaceholderFragment
 )
 
 // PlaceholderFragment is a part of a placeholder within the document.xml
@@ -99,8 +102,11 @@ func (p PlaceholderFragment) TextLength(docBytes []byte) int64 {
 // String spits out the most important bits and pieces of a fragment and can be used for debugging purposes.
 func (p PlaceholderFragment) String(docBytes []byte) string {
 	format := "fragment %d in %s with fragment text-positions: [%d:%d] '%s'"
-	return fmt.Sprintf(format, p.ID, p.Run.String(docBytes),
-		p.Position.Start, p.Position.End, docBytes[p.Run.Text.OpenTag.End+p.Position.Start:p.Run.Text.OpenTag.End+p.Position.End])
+	if p.Run == nil {
+		return fmt.Sprintf("fragment %d without run", p.ID)
+	}
+	text, _ := safeSlice(docBytes, p.Run.Text.OpenTag.End+p.Position.Start, p.Run.Text.OpenTag.End+p.Position.End)
+	return fmt.Sprintf(format, p.ID, p.Run.String(docBytes), p.Position.Start, p.Position.End, text)
 }
 
 // Valid returns true if all positions of the fragment are valid.
@@ -112,13 +118,15 @@ func (p PlaceholderFragment) Valid() bool {
 		p.Position.Valid()
 }
 
-// NewFragmentID returns the next Fragment.ID
+// NewFragmentID returns the next Fragment.ID of the global counter.
+// ParsePlaceholders does not use the global counter, fragment IDs are scoped to the parsed placeholders instead.
 func NewFragmentID() int {
-	fragmentId += 1
-	return fragmentId
+	return int(atomic.AddInt64(&fragmentId, 1))
 }
 
-// ResetFragmentIdCounter will reset the fragmentId counter to 0
+// ResetFragmentIdCounter will reset the global fragmentId counter to 0
+//
+// Deprecated: Fragment IDs of parsed placeholders are scoped to them, resetting the counter is not necessary.
 func ResetFragmentIdCounter() {
-	fragmentId = 0
+	atomic.StoreInt64(&fragmentId, 0)
 }
@@ -1,6 +1,9 @@
 package docx
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 var (
 	textMapping = PlaceholderMap{
@@ -52,8 +55,280 @@ func TestPlaceholder_AssembleFullPlaceholders(t *testing.T) {
 	openPos := []int{10, 18}
 	closePos := []int{17, 25}
 
-	placeholders := assembleFullPlaceholders(&Run{}, openPos, closePos)
+	placeholders := assembleFullPlaceholders(&Run{}, openPos, closePos, defaultDelimiters)
 	if len(placeholders) != expectedCount {
 		t.Errorf("not all full placeholders were parsed, want=%d, have=%d", expectedCount, len(placeholders))
 	}
 }
+
+func TestPlaceholder_ZeroValues(t *testing.T) {
+	docBytes := []byte("<w:t>{foo}</w:t>")
+
+	empty := Placeholder{}
+	if empty.Text(docBytes) != "" || empty.StartPos() != 0 || empty.EndPos() != 0 || empty.Valid() {
+		t.Error("a placeholder without fragments must return zero values")
+	}
+
+	// fragment offsets which are out of bounds of a zero-valued run
+	outOfBounds := assembleFullPlaceholders(&Run{}, []int{10, 18}, []int{17, 25}, defaultDelimiters)
+	for _, placeholder := range outOfBounds {
+		if text := placeholder.Text(docBytes); text != "" {
+			t.Errorf("unexpected text for out of bounds fragment, want=%q, have=%q", "", text)
+		}
+	}
+
+	withoutRun := Placeholder{Fragments: []*PlaceholderFragment{{Position: Position{0, 5}}}}
+	if withoutRun.Text(docBytes) != "" || withoutRun.StartPos() != 0 || withoutRun.EndPos() != 0 || withoutRun.Valid() {
+		t.Error("a placeholder with a fragment without run must return zero values")
+	}
+}
+
+func TestNormalizePlaceholder(t *testing.T) {
+	tests := []struct {
+		placeholder string
+		expected    string
+	}{
+		{"{foo}", "{foo}"},
+		{"{ foo }", "{foo}"},
+		{"{foo\u00a0}", "{foo}"},
+		{"{\u200bfoo\u200d}", "{foo}"},
+		{"{foo \u00a0 bar}", "{foo bar}"},
+		{" foo\ufeff", "foo"},
+	}
+	for _, tt := range tests {
+		if normalized := NormalizePlaceholder(tt.placeholder); normalized != tt.expected {
+			t.Errorf("NormalizePlaceholder(%q), want=%q, have=%q", tt.placeholder, tt.expected, normalized)
+		}
+	}
+}
+
+func TestParsePlaceholders_MultiByteDelimiters(t *testing.T) {
+	docx := newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/guillemets.xml"))})
+	doc, err := OpenBytes(docx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := doc.SetDelimiters('«', '»'); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var texts []string
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		texts = append(texts, placeholder.Text(doc.files[DocumentXml]))
+	}
+	expected := []string{"«key»", "«street»", "«city»", "«zip»"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%v, have=%v", expected, texts)
+	}
+
+	err = doc.ReplaceAll(PlaceholderMap{"key": "Müller", "street": "Hauptstraße 1", "city": "Köln", "zip": " 50667"})
+	if err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+	expectedText := "Sehr geehrte(r) Müller, Straße: Hauptstraße 1, Köln 50667 und {braces}"
+	if text := writtenText(t, doc, DocumentXml); text != expectedText {
+		t.Errorf("unexpected text, want=%s, have=%s", expectedText, text)
+	}
+
+	// the delimiters only apply to the document which set them
+	other, err := OpenBytes(docx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if open, close := other.Delimiters(); open != OpenDelimiter || close != CloseDelimiter {
+		t.Errorf("unexpected delimiters of another document, want=%c%c, have=%c%c", OpenDelimiter, CloseDelimiter, open, close)
+	}
+	if err := other.SetDelimiters('«', '«'); err == nil {
+		t.Error("expected an error for equal delimiters")
+	}
+}
+
+func TestPlaceholder_RunIDsAndFileOffsets(t *testing.T) {
+	docBytes := []byte(`<w:p><w:r><w:t>Hello {na</w:t></w:r><w:r><w:t>me}!</w:t></w:r></w:p>`)
+
+	parser := NewRunParser(docBytes)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), docBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(placeholders) != 1 {
+		t.Fatalf("unexpected number of placeholders, want=%d, have=%d", 1, len(placeholders))
+	}
+	placeholder := placeholders[0]
+
+	runs := parser.Runs()
+	if ids, expected := placeholder.RunIDs(), []int{runs[0].ID, runs[1].ID}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("unexpected run IDs, want=%v, have=%v", expected, ids)
+	}
+
+	var text string
+	for _, offset := range placeholder.FileOffsets(docBytes) {
+		text += string(docBytes[offset.Start:offset.End])
+	}
+	if text != "{name}" {
+		t.Errorf("unexpected text of the offsets, want=%s, have=%s", "{name}", text)
+	}
+
+	if offsets := placeholder.FileOffsets(docBytes[:20]); len(offsets) != 0 {
+		t.Errorf("offsets outside of the data were not skipped, have=%v", offsets)
+	}
+}
+
+func TestParsePlaceholdersDetailed(t *testing.T) {
+	docBytes := []byte(`<w:p><w:r><w:t>{valid}</w:t></w:r><w:r><w:t>{foo{bar}}</w:t></w:r>` +
+		`<w:r><w:t>{split</w:t></w:r><w:r><w:t>-valid}</w:t></w:r><w:r><w:t>{never closed</w:t></w:r></w:p>`)
+	parser := NewRunParser(docBytes)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	runs := parser.Runs()
+
+	result, err := ParsePlaceholdersDetailed(runs, docBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, placeholder := range result.Placeholders {
+		texts = append(texts, placeholder.Text(docBytes))
+	}
+	if expected := []string{"{valid}", "{split-valid}"}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%q, have=%q", expected, texts)
+	}
+
+	expected := []SkippedPlaceholder{
+		{Reason: SkipNested, RunID: runs[1].ID, Text: "{foo{bar}}"},
+		{Reason: SkipUnclosed, RunID: runs[4].ID, Text: "{never closed"},
+	}
+	if !reflect.DeepEqual(result.Skipped, expected) {
+		t.Errorf("unexpected skipped placeholders\nwant=%v\nhave=%v", expected, result.Skipped)
+	}
+
+	// ParsePlaceholders returns the same placeholders
+	placeholders, err := ParsePlaceholders(runs, docBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(placeholders) != len(result.Placeholders) {
+		t.Errorf("unexpected number of placeholders, want=%d, have=%d", len(result.Placeholders), len(placeholders))
+	}
+}
+
+func TestParsePlaceholders_CombiningMarks(t *testing.T) {
+	template := readFile(t, "./test/combining_marks.xml")
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// delimiters followed by a combining mark or a zero-width joiner are no delimiters
+	var texts []string
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		texts = append(texts, placeholder.Text(doc.files[DocumentXml]))
+	}
+	expected := []string{"{name}", "{city}", "{na\u0308me}", "{title}", "{name}", "{b}"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%q, have=%q", expected, texts)
+	}
+
+	err = doc.ReplaceAll(PlaceholderMap{"name": "Zoe\u0308", "city": "Ko\u0308ln", "na\u0308me": "Rene\u0301", "title": "Dr.", "a": "A", "b": "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedText := "Cafe\u0301 Zoe\u0308 a\u0300 Ko\u0308ln" + "Rene\u0301 \u0915\u094d\u0937 Dr." +
+		"{\u0301accent} {\u200dzwj} Zoe\u0308" + "{city}\u20dd" + "{a}\u0301 and B end"
+	if text := writtenText(t, doc, DocumentXml); text != expectedText {
+		t.Errorf("unexpected text, want=%q, have=%q", expectedText, text)
+	}
+}
+
+func TestParsePlaceholders_EmptyPlaceholder(t *testing.T) {
+	body := `<w:p><w:r><w:t>An empty set {} is written as {} in {lang}.</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Split {</w:t></w:r><w:r><w:t>} as well</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	// empty placeholders are literals, they are neither parsed nor counted
+	var texts []string
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		texts = append(texts, placeholder.Text(doc.files[DocumentXml]))
+	}
+	if expected := []string{"{lang}"}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%q, have=%q", expected, texts)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"": "empty", "lang": "Go"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "An empty set {} is written as {} in Go." + "Split {} as well"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%q, have=%q", expected, text)
+	}
+}
+
+func TestPlaceholder_Replaced(t *testing.T) {
+	body := `<w:p><w:r><w:t>{na</w:t></w:r><w:r><w:t>me} and {other}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		if placeholder.Replaced() {
+			t.Error("expected no placeholder to be replaced after parsing")
+		}
+	}
+	if err := doc.Replace("name", "Alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := doc.GetFile(DocumentXml)
+	placeholders := doc.filePlaceholders[DocumentXml]
+	if states, expected := []bool{placeholders[0].Replaced(), placeholders[1].Replaced()}, []bool{true, false}; !reflect.DeepEqual(states, expected) {
+		t.Fatalf("unexpected replaced state of {name} and {other}, want=%v, have=%v", expected, states)
+	}
+	// the first fragment holds the value, the positions of unreplaced placeholders stay valid
+	replaced := placeholders[0]
+	if value := string(data[replaced.StartPos():replaced.Fragments[0].EndPos()]); value != "Alice" {
+		t.Errorf("first fragment does not hold the value, have=%q", value)
+	}
+	if text := string(data[placeholders[1].StartPos():placeholders[1].EndPos()]); text != "{other}" {
+		t.Errorf("positions of {other} are invalid, have=%q", text)
+	}
+}
+
+func TestParsePlaceholders_StrayCloseDelimiter(t *testing.T) {
+	tests := []struct {
+		runs     []string
+		expected []string
+	}{
+		{runs: []string{"{a} }"}, expected: []string{"{a}"}},
+		{runs: []string{"{a}} {b}"}, expected: []string{"{a}", "{b}"}},
+		{runs: []string{"} {a}"}, expected: []string{"{a}"}},
+		{runs: []string{"{gre", "eting} and} {name}"}, expected: []string{"{greeting}", "{name}"}},
+	}
+
+	for _, tt := range tests {
+		var body string
+		for _, run := range tt.runs {
+			body += `<w:r><w:t xml:space="preserve">` + run + `</w:t></w:r>`
+		}
+		docBytes := []byte(`<w:p>` + body + `</w:p>`)
+		parser := NewRunParser(docBytes)
+		if err := parser.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		placeholders, err := ParsePlaceholders(parser.Runs(), docBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var texts []string
+		for _, placeholder := range placeholders {
+			texts = append(texts, placeholder.Text(docBytes))
+		}
+		if !reflect.DeepEqual(texts, tt.expected) {
+			t.Errorf("unexpected placeholders of %q, want=%q, have=%q", tt.runs, tt.expected, texts)
+		}
+	}
+}
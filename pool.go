@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"sync"
+)
+
+// TemplatePool hands out ready-to-fill copies of a parsed template for high-throughput rendering.
+// Documents returned to the pool are reset and reused, which avoids parsing the template for every
+// render and reduces the allocations compared to Clone.
+//
+// The usage is:
+//
+//	doc := pool.Get()
+//	err := doc.ReplaceAll(placeholderMap)
+//	err = doc.Write(w)
+//	pool.Put(doc)
+//
+// A document must not be used anymore once it was put back, this includes the bytes returned by GetFile.
+// The pool is safe for concurrent use, the documents it hands out are not.
+type TemplatePool struct {
+	template *Document
+	pool     sync.Pool
+}
+
+// NewTemplatePool creates a pool of copies of the given template.
+// The template must not be modified afterwards and must not be closed before the pool is no longer used.
+func NewTemplatePool(template *Document) *TemplatePool {
+	p := &TemplatePool{template: template}
+	p.pool.New = func() interface{} {
+		return template.Clone()
+	}
+	return p
+}
+
+// Get returns a document in the state of the template.
+func (p *TemplatePool) Get() *Document {
+	return p.pool.Get().(*Document)
+}
+
+// Put resets the document to the state of the template and returns it to the pool.
+// Documents which were not handed out by the pool are ignored.
+func (p *TemplatePool) Put(doc *Document) {
+	if doc == nil || doc.zipFile != p.template.zipFile {
+		return
+	}
+	p.Reset(doc)
+	p.pool.Put(doc)
+}
+
+// Reset discards all modifications of the document, restoring the state of the template.
+func (p *TemplatePool) Reset(doc *Document) {
+	p.template.cloneInto(doc)
+}
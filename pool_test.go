@@ -0,0 +1,82 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTemplatePool(t *testing.T) {
+	template, err := Open("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer template.Close()
+	pool := NewTemplatePool(template)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 4; j++ {
+				value := fmt.Sprintf("value-%d-%d", i, j)
+				doc := pool.Get()
+				if err := doc.ReplaceAll(PlaceholderMap{"key": value, "key-with-dash": j}); err != nil {
+					t.Error("replacing failed", err)
+					return
+				}
+				text := writtenText(t, doc, DocumentXml)
+				if !strings.Contains(text, value) || strings.Contains(text, "{key}") {
+					t.Errorf("document %d was not rendered from the template", i)
+				}
+				pool.Put(doc)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if strings.Contains(writtenText(t, template, DocumentXml), "value-") {
+		t.Error("template must not be modified")
+	}
+}
+
+func BenchmarkTemplatePool(b *testing.B) {
+	template, err := Open("./test/template.docx")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer template.Close()
+	pool := NewTemplatePool(template)
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		doc := pool.Get()
+		if err := doc.ReplaceAll(PlaceholderMap{"key": "value"}); err != nil {
+			b.Fatal(err)
+		}
+		if err := doc.Write(new(bytes.Buffer)); err != nil {
+			b.Fatal(err)
+		}
+		pool.Put(doc)
+	}
+}
+
+func BenchmarkTemplatePool_Reopen(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		doc, err := Open("./test/template.docx")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := doc.ReplaceAll(PlaceholderMap{"key": "value"}); err != nil {
+			b.Fatal(err)
+		}
+		if err := doc.Write(new(bytes.Buffer)); err != nil {
+			b.Fatal(err)
+		}
+		doc.Close()
+	}
+}
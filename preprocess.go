@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+)
+
+// Preprocessor transforms the raw content of a part before it is parsed, see OpenWithPreprocessor.
+type Preprocessor func(partName string, raw []byte) []byte
+
+// OpenWithPreprocessor opens the file pointed to by path just like Open, but passes the content of every part
+// which is parsed for placeholders (e.g. 'word/document.xml') to the preprocessor before the runs are parsed.
+// This allows to normalize the parts without changes to the library, e.g. by removing spelling markers or
+// bookmarks which fragment the placeholders. The preprocessed content is written on Write().
+//
+// The preprocessor must return well-formed XML, otherwise parsing fails.
+func OpenWithPreprocessor(path string, preprocessor Preprocessor) (*Document, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open .docx docxFile: %s", err)
+	}
+	stat, err := fh.Stat()
+	if err != nil {
+		_ = fh.Close()
+		return nil, fmt.Errorf("unable to stat .docx docxFile: %s", err)
+	}
+	rc, err := zip.NewReader(fh, stat.Size())
+	if err != nil {
+		_ = fh.Close()
+		return nil, fmt.Errorf("unable to open zip reader: %s", err)
+	}
+
+	doc, err := newDocument(rc, path, fh, preprocessor)
+	if err != nil {
+		_ = fh.Close()
+		return nil, err
+	}
+	return doc, nil
+}
+
+// preprocess applies the preprocessor of the document to the part, if there is one.
+func (d *Document) preprocess(name string, data []byte) []byte {
+	if d.preprocessor == nil || data == nil {
+		return data
+	}
+	return d.preprocessor(name, data)
+}
@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestOpenWithPreprocessor(t *testing.T) {
+	body := `<w:p><w:r><w:t>{na</w:t></w:r><w:bookmarkStart w:id="0" w:name="_GoBack"/><w:bookmarkEnd w:id="0"/>` +
+		`<w:r><w:t>me}</w:t></w:r></w:p>`
+	path := filepath.Join(t.TempDir(), "template.docx")
+	if err := os.WriteFile(path, newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml(body)}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bookmarkRegex := regexp.MustCompile(`<w:bookmark(Start|End)\b[^>]*/>`)
+	var parts []string
+	doc, err := OpenWithPreprocessor(path, func(partName string, raw []byte) []byte {
+		parts = append(parts, partName)
+		return bookmarkRegex.ReplaceAll(raw, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	sort.Strings(parts)
+	expected := append(append([]string{DocumentXml}, doc.footerFiles...), doc.headerFiles...)
+	sort.Strings(expected)
+	if strings.Join(parts, ",") != strings.Join(expected, ",") {
+		t.Errorf("parsed parts were not preprocessed, want=%q, have=%q", expected, parts)
+	}
+
+	// the preprocessed content is parsed and written
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "value", "key": "value"}); err != nil {
+		t.Fatal(err)
+	}
+	if document := string(writtenFile(t, doc, DocumentXml)); strings.Contains(document, "bookmark") {
+		t.Error("expected the bookmarks to be removed")
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "value" {
+		t.Errorf("unexpected text, want=%q, have=%q", "value", text)
+	}
+}
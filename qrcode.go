@@ -0,0 +1,74 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoQRGenerator is returned by ReplaceQRCode if the options do not contain a QRGenerator.
+var ErrNoQRGenerator = errors.New("no QR code generator configured")
+
+// QRGenerator encodes data as a QR code (or any other kind of barcode).
+// The library does not depend on a specific QR code package, any package can be plugged in, e.g.:
+//
+//	docx.QRGeneratorFunc(func(data string, size int) ([]byte, error) {
+//		return qrcode.Encode(data, qrcode.Medium, size)
+//	})
+type QRGenerator interface {
+	// Generate returns the code of the data as a square image (e.g. a png) with the given size in pixels.
+	Generate(data string, size int) ([]byte, error)
+}
+
+// QRGeneratorFunc adapts a function to the QRGenerator interface.
+type QRGeneratorFunc func(data string, size int) ([]byte, error)
+
+// Generate calls the function.
+func (f QRGeneratorFunc) Generate(data string, size int) ([]byte, error) {
+	return f(data, size)
+}
+
+// QROptions configure the QR codes inserted by ReplaceQRCode.
+type QROptions struct {
+	// Generator creates the image of the QR code, it is required.
+	Generator QRGenerator
+	// Size of the generated image in pixels. 0 results in the default of 256.
+	Size int
+	// DisplaySize is the width and height of the QR code inside the document in points. 0 results in the default
+	// of 72 (one inch).
+	DisplaySize int
+	// AltText is the description of the image. If empty, the encoded data is used.
+	AltText string
+}
+
+// withDefaults returns the options with all unset values replaced by their defaults.
+func (o QROptions) withDefaults() QROptions {
+	if o.Size <= 0 {
+		o.Size = 256
+	}
+	if o.DisplaySize <= 0 {
+		o.DisplaySize = 72
+	}
+	return o
+}
+
+// ReplaceQRCode replaces all placeholders with the given key by a QR code of the data, e.g. a payment link.
+// The code is generated by the Generator of the options and inserted as an inline image, the text around the
+// placeholder is kept. All modified files are parsed again afterwards.
+//
+// If no placeholder with the given key exists, ErrPlaceholderNotFound is returned.
+func (d *Document) ReplaceQRCode(key, data string, opts QROptions) error {
+	if opts.Generator == nil {
+		return ErrNoQRGenerator
+	}
+	opts = opts.withDefaults()
+	if opts.AltText == "" {
+		opts.AltText = data
+	}
+
+	image, err := opts.Generator.Generate(data, opts.Size)
+	if err != nil {
+		return fmt.Errorf("unable to generate QR code for %s: %w", key, err)
+	}
+	size := int64(opts.DisplaySize) * EmusPerPoint
+	return d.replaceWithImage(key, ImageData{Data: image}, size, size, opts.AltText)
+}
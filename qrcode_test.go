@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testQRGenerator returns a blank png of the requested size instead of a real QR code.
+var testQRGenerator = QRGeneratorFunc(func(data string, size int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, size, size))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+})
+
+func TestDocument_ReplaceQRCode(t *testing.T) {
+	body := `<w:p><w:r><w:t>Pay here: {qr} thanks</w:t></w:r></w:p><w:p><w:r><w:t>{other}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	if err := doc.ReplaceQRCode("qr", "https://example.com/pay", QROptions{}); !errors.Is(err, ErrNoQRGenerator) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrNoQRGenerator, err)
+	}
+	if err := doc.ReplaceQRCode("missing", "data", QROptions{Generator: testQRGenerator}); !errors.Is(err, ErrPlaceholderNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrPlaceholderNotFound, err)
+	}
+	if err := doc.ReplaceQRCode("qr", "https://example.com/pay", QROptions{Generator: testQRGenerator, Size: 64}); err != nil {
+		t.Fatal(err)
+	}
+	// the document is parsed again, thus the remaining placeholders can still be replaced
+	if err := doc.ReplaceAll(PlaceholderMap{"other": "done"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if text := writtenText(t, doc, DocumentXml); text != "Pay here:  thanksdone" {
+		t.Errorf("unexpected text, want=%q, have=%q", "Pay here:  thanksdone", text)
+	}
+	document := string(writtenFile(t, doc, DocumentXml))
+	for _, expected := range []string{
+		`xmlns:wp="` + wordprocessingDrawingNamespace + `"`,
+		`<wp:extent cx="914400" cy="914400"/>`,
+		`descr="https://example.com/pay"`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected document to contain %s", expected)
+		}
+	}
+
+	rels, err := doc.Relationships(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target string
+	for _, rel := range rels {
+		if rel.Type == ImageRelationshipType && strings.Contains(document, `r:embed="`+rel.ID+`"`) {
+			target = rel.Target
+		}
+	}
+	if target != "media/image1.png" {
+		t.Fatalf("unexpected image of the drawing, want=%s, have=%s", "media/image1.png", target)
+	}
+	img, err := png.Decode(bytes.NewReader(writtenFile(t, doc, "word/media/image1.png")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Dx(); size != 64 {
+		t.Errorf("unexpected image size, want=%d, have=%d", 64, size)
+	}
+	if contentType, _ := doc.contentType("word/media/image1.png"); contentType != "image/png" {
+		t.Errorf("unexpected content type, want=%s, have=%s", "image/png", contentType)
+	}
+}
@@ -3,6 +3,10 @@ package docx
 import "io"
 
 // Reader is a very basic io.Reader implementation which is capable of returning the current position.
+// It is the primitive of the RunParser and can be used to build custom parsers for other parts as well.
+//
+// Since it implements io.ByteReader, an xml.Decoder reading from it does not buffer the input. Thus Pos() points
+// right behind the last token returned by the decoder, e.g. behind the '>' of a tag. See ElementPositions.
 type Reader struct {
 	str      string
 	i        int64
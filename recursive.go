@@ -0,0 +1,81 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxRecursionDepth limits how deep values may reference other values if recursive values are enabled.
+const MaxRecursionDepth = 10
+
+// ErrRecursiveValue is returned if recursive values reference each other in a cycle or are nested too deep.
+var ErrRecursiveValue = errors.New("unable to expand recursive value")
+
+// SetRecursiveValues enables or disables expanding placeholders inside the values of the PlaceholderMap.
+// If enabled, a value like '{greeting}, World' is expanded using the value of the key 'greeting' before it is
+// inserted. Placeholders of keys which are not part of the PlaceholderMap are inserted as they are.
+// Values may be nested up to MaxRecursionDepth, cycles are reported as ErrRecursiveValue. It is disabled by default.
+func (d *Document) SetRecursiveValues(recursive bool) {
+	d.recursiveValues = recursive
+}
+
+// expandValues returns a copy of the PlaceholderMap in which all values are expanded recursively.
+func expandValues(placeholderMap PlaceholderMap, delims delimiters) (PlaceholderMap, error) {
+	open, close := regexp.QuoteMeta(string(delims.open)), regexp.QuoteMeta(string(delims.close))
+	placeholderRegex := regexp.MustCompile(open + `([^` + open + close + `]*)` + close)
+
+	// expansion keeps track of the expanded value and how deep the references are nested
+	type expansion struct {
+		value string
+		depth int
+	}
+	expanded := make(map[string]expansion, len(placeholderMap))
+
+	var expand func(key string, path []string) (expansion, error)
+	expand = func(key string, path []string) (expansion, error) {
+		if done, exists := expanded[key]; exists {
+			return done, nil
+		}
+		for _, visited := range path {
+			if visited == key {
+				return expansion{}, fmt.Errorf("%w: cycle %s", ErrRecursiveValue, strings.Join(append(path, key), " -> "))
+			}
+		}
+		path = append(path, key)
+
+		var err error
+		depth := 0
+		value := placeholderRegex.ReplaceAllStringFunc(fmt.Sprint(placeholderMap[key]), func(placeholder string) string {
+			referenced := delims.remove(placeholder)
+			if _, exists := placeholderMap[referenced]; !exists || err != nil {
+				return placeholder
+			}
+			var nested expansion
+			nested, err = expand(referenced, path)
+			if nested.depth+1 > depth {
+				depth = nested.depth + 1
+			}
+			return nested.value
+		})
+		if err != nil {
+			return expansion{}, err
+		}
+		if depth > MaxRecursionDepth {
+			return expansion{}, fmt.Errorf("%w: %s is nested deeper than %d", ErrRecursiveValue, key, MaxRecursionDepth)
+		}
+		expanded[key] = expansion{value: value, depth: depth}
+		return expanded[key], nil
+	}
+
+	result := make(PlaceholderMap, len(placeholderMap))
+	for key := range placeholderMap {
+		expandedValue, err := expand(key, nil)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = expandedValue.value
+	}
+	return result, nil
+}
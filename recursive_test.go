@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocument_SetRecursiveValues(t *testing.T) {
+	body := `<w:p><w:r><w:t>{message}</w:t></w:r></w:p>`
+
+	tests := []struct {
+		name         string
+		recursive    bool
+		values       PlaceholderMap
+		expectedText string
+		expectedErr  error
+	}{
+		{
+			name:         "disabled",
+			values:       PlaceholderMap{"message": "{greeting}, World", "greeting": "Hello"},
+			expectedText: "{greeting}, World",
+		},
+		{
+			name:         "nested values",
+			recursive:    true,
+			values:       PlaceholderMap{"message": "{greeting}, {name}", "greeting": "Hello", "name": "{first} {last}", "first": "Jane", "last": 42},
+			expectedText: "Hello, Jane 42",
+		},
+		{
+			name:         "unknown keys are kept",
+			recursive:    true,
+			values:       PlaceholderMap{"message": "{greeting}, {unknown}", "greeting": "Hi"},
+			expectedText: "Hi, {unknown}",
+		},
+		{
+			name:        "cycle",
+			recursive:   true,
+			values:      PlaceholderMap{"message": "{a}", "a": "{b}", "b": "{a}"},
+			expectedErr: ErrRecursiveValue,
+		},
+		{
+			name:        "self reference",
+			recursive:   true,
+			values:      PlaceholderMap{"message": "{message}!"},
+			expectedErr: ErrRecursiveValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, body)
+			doc.SetRecursiveValues(tt.recursive)
+
+			err := doc.ReplaceAll(tt.values)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("unexpected error, want=%v, have=%v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text := writtenText(t, doc, DocumentXml); text != tt.expectedText {
+				t.Errorf("unexpected text, want=%s, have=%s", tt.expectedText, text)
+			}
+		})
+	}
+}
+
+func TestExpandValues_MaxDepth(t *testing.T) {
+	values := PlaceholderMap{"0": "end"}
+	for i := 1; i <= MaxRecursionDepth+1; i++ {
+		values[string(rune('a'+i))] = "{" + string(rune('a'+i-1)) + "}"
+	}
+	values["a"] = "{0}"
+
+	if _, err := expandValues(values, defaultDelimiters); !errors.Is(err, ErrRecursiveValue) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrRecursiveValue, err)
+	}
+}
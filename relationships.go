@@ -0,0 +1,144 @@
+package docx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+)
+
+const (
+	// PackageRelationshipsXml holds the relationships of the package itself, e.g. to the main document.
+	PackageRelationshipsXml = "_rels/.rels"
+	// RelationshipsContentType is the content type of all relationships parts.
+	RelationshipsContentType = "application/vnd.openxmlformats-package.relationships+xml"
+	// TargetModeExternal marks relationships which point outside of the archive (e.g. hyperlinks).
+	TargetModeExternal = "External"
+)
+
+// Relationship is a single <Relationship> of a relationships part (*.rels).
+// The Target is relative to the directory of the source part unless it is absolute or external (see TargetMode).
+type Relationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+// relationshipsXml is the root element of a relationships part.
+type relationshipsXml struct {
+	Relationships []Relationship `xml:"Relationship"`
+}
+
+// relationshipsPartName returns the name of the relationships part which belongs to the given part.
+// The relationships of 'word/document.xml' for example are stored in 'word/_rels/document.xml.rels'.
+// The empty part refers to the package itself, its relationships are stored in the PackageRelationshipsXml.
+func relationshipsPartName(part string) string {
+	if part == "" {
+		return PackageRelationshipsXml
+	}
+	return path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+}
+
+// relationshipTargetPath resolves the target of a relationship to the name of the referenced file inside the archive.
+// Targets are relative to the directory of the source part, unless they are absolute.
+func relationshipTargetPath(part, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(part), target)
+}
+
+// relationshipTarget returns the target of a relationship from the part to the given file inside the archive.
+// It is the inverse of relationshipTargetPath.
+func relationshipTarget(part, file string) string {
+	if dir := path.Dir(part) + "/"; strings.HasPrefix(file, dir) {
+		return strings.TrimPrefix(file, dir)
+	}
+	return "/" + file
+}
+
+// Relationships returns all relationships of the given part, e.g. 'word/document.xml', in the order of its
+// relationships part. The empty part name refers to the relationships of the package itself ('_rels/.rels').
+// Relationships which were added through the Document API are included.
+// If the part does not have any relationships, an empty slice is returned.
+func (d *Document) Relationships(part string) ([]Relationship, error) {
+	data, err := d.readRawFile(relationshipsPartName(part))
+	if errors.Is(err, ErrFileNotFound) {
+		return []Relationship{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read relationships of %s: %w", part, err)
+	}
+	var rels relationshipsXml
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("unable to parse relationships of %s: %s", part, err)
+	}
+	if rels.Relationships == nil {
+		return []Relationship{}, nil
+	}
+	return rels.Relationships, nil
+}
+
+// relationship returns the relationship of the part with the given id.
+func (d *Document) relationship(part, id string) (Relationship, error) {
+	rels, err := d.Relationships(part)
+	if err != nil {
+		return Relationship{}, err
+	}
+	for _, rel := range rels {
+		if rel.ID == id {
+			return rel, nil
+		}
+	}
+	return Relationship{}, fmt.Errorf("relationship %s of %s does not exist", id, part)
+}
+
+// AddRelationship adds a new relationship from the given part to the target and returns its id.
+// The id is unique among the relationships of the part. Targets of external relationships (e.g. the URL of a
+// hyperlink) are marked with TargetModeExternal, all other targets are relative to the directory of the part.
+// If the part does not have any relationships yet, the relationships part is created.
+// The relationships part is written on Write().
+func (d *Document) AddRelationship(part, relType, target string, external bool) (string, error) {
+	rels, err := d.Relationships(part)
+	if err != nil {
+		return "", err
+	}
+
+	// ids only need to be unique, Word uses 'rId' followed by a number
+	ids := make(map[string]bool, len(rels))
+	max := 0
+	for _, rel := range rels {
+		ids[rel.ID] = true
+		var n int
+		if _, err := fmt.Sscanf(rel.ID, "rId%d", &n); err == nil && n > max {
+			max = n
+		}
+	}
+	id := fmt.Sprintf("rId%d", max+1)
+	for n := max + 2; ids[id]; n++ {
+		id = fmt.Sprintf("rId%d", n)
+	}
+
+	relsPart := relationshipsPartName(part)
+	data, err := d.readRawFile(relsPart)
+	switch {
+	case errors.Is(err, ErrFileNotFound):
+		data = []byte(XmlDeclaration + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`)
+		if err := d.registerContentType(relsPart, RelationshipsContentType); err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	}
+	targetMode := ""
+	if external {
+		targetMode = fmt.Sprintf(` TargetMode="%s"`, TargetModeExternal)
+	}
+	rel := fmt.Sprintf(`<Relationship Id="%s" Type="%s" Target="%s"%s/>`, id, html.EscapeString(relType), html.EscapeString(target), targetMode)
+	d.setRawFile(relsPart, insertBeforeClosingTag(data, "</Relationships>", rel))
+	return id, nil
+}
@@ -0,0 +1,147 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDocument_Relationships(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		"word/_rels/header1.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" ` +
+			`Target="https://example.com" TargetMode="External"/></Relationships>`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rels, err := doc.Relationships(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 8 {
+		t.Fatalf("unexpected number of relationships of the document, want=%d, have=%d", 8, len(rels))
+	}
+	expected := Relationship{ID: "rId7", Type: HeaderRelationshipType, Target: "header1.xml"}
+	if rels[6] != expected {
+		t.Errorf("unexpected relationship, want=%v, have=%v", expected, rels[6])
+	}
+
+	rels, err = doc.Relationships("word/header1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRels := []Relationship{{
+		ID:         "rId1",
+		Type:       "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink",
+		Target:     "https://example.com",
+		TargetMode: TargetModeExternal,
+	}}
+	if !reflect.DeepEqual(rels, expectedRels) {
+		t.Errorf("unexpected relationships of the header, want=%v, have=%v", expectedRels, rels)
+	}
+
+	// parts without relationships have an empty list
+	rels, err = doc.Relationships("word/footer1.xml")
+	if err != nil || rels == nil || len(rels) != 0 {
+		t.Errorf("unexpected relationships, want=%v, have=%v (%v)", []Relationship{}, rels, err)
+	}
+
+	// relationships added through the Document API are part of the list
+	id, err := doc.AddRelationship("word/footer1.xml", HeaderRelationshipType, "header1.xml", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rels, err := doc.Relationships("word/footer1.xml"); err != nil || len(rels) != 1 || rels[0].ID != id {
+		t.Errorf("added relationship is missing, want=%s, have=%v (%v)", id, rels, err)
+	}
+}
+
+func TestDocument_AddRelationship(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hyperlinkType := "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	id, err := doc.AddRelationship(DocumentXml, hyperlinkType, "https://example.com/?a=1&b=2", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "rId9" {
+		t.Errorf("unexpected id, want=%s, have=%s", "rId9", id)
+	}
+	next, err := doc.AddRelationship(DocumentXml, hyperlinkType, "https://example.org", true)
+	if err != nil || next == id {
+		t.Fatalf("id is not unique, have=%s (%v)", next, err)
+	}
+
+	// the relationships part is written, even if the part did not have any relationships before
+	if _, err := doc.AddRelationship("word/footer1.xml", HeaderRelationshipType, "header1.xml", false); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, err := written.Relationships(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Relationship{ID: id, Type: hyperlinkType, Target: "https://example.com/?a=1&b=2", TargetMode: TargetModeExternal}
+	if len(rels) != 10 || rels[8] != expected {
+		t.Errorf("added relationship is missing, want=%v, have=%v", expected, rels)
+	}
+	if rels, err := written.Relationships("word/footer1.xml"); err != nil || len(rels) != 1 || rels[0].TargetMode != "" {
+		t.Errorf("unexpected relationships of the footer, have=%v (%v)", rels, err)
+	}
+}
+
+func TestDocument_Relationships_Unreadable(t *testing.T) {
+	template, err := zip.OpenReader("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer template.Close()
+
+	// a relationships part with an unsupported compression method exists, but cannot be read
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range template.File {
+		if err := zipWriter.Copy(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := zipWriter.CreateRaw(&zip.FileHeader{Name: "word/_rels/footer1.xml.rels", Method: 99}); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rels, err := doc.Relationships("word/footer1.xml"); err == nil || errors.Is(err, ErrFileNotFound) {
+		t.Errorf("expected the read error, have=%v (%v)", err, rels)
+	}
+	if _, err := doc.AddRelationship("word/footer1.xml", HeaderRelationshipType, "header1.xml", false); err == nil {
+		t.Error("expected the read error instead of a new relationships part")
+	}
+	if _, exists := doc.rawFiles["word/_rels/footer1.xml.rels"]; exists {
+		t.Error("expected the unreadable relationships part to be kept")
+	}
+
+	_, err = doc.readRawFile("word/_rels/footer2.xml.rels")
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("unexpected error of a missing file, want=%v, have=%v", ErrFileNotFound, err)
+	}
+}
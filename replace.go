@@ -1,26 +1,45 @@
 package docx
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"html"
-	"strings"
+	"sort"
 	"sync"
 )
 
 var (
 	// ErrPlaceholderNotFound is returned if there is no placeholder inside the document.
 	ErrPlaceholderNotFound = errors.New("placeholder not found in document")
+	// ErrUnsafeCut is returned if replacing a fragment would cut outside of its text, e.g. into a <w:sectPr>.
+	ErrUnsafeCut = errors.New("fragment cut is not within its text")
 )
 
 // Replacer is the key struct which works on the parsed DOCX document.
 type Replacer struct {
 	document     []byte
 	placeholders []*Placeholder
-	distinctRuns []*Run // slice of all distinct runs extracted from the placeholders used for validation
+	distinctRuns []*Run                          // slice of all distinct runs extracted from the placeholders used for validation
+	runFragments map[*Run][]*PlaceholderFragment // index of the fragments by their run, see fragmentsInRun
+	emptiedRuns  []*Run                          // runs whose text was cut entirely, see RemoveEmptyRuns
 	ReplaceCount int
 	BytesChanged int64
 	mu           sync.Mutex
+
+	// NormalizeWhitespace enables matching placeholders and keys after normalizing their whitespace.
+	// See NormalizePlaceholder for the applied normalization.
+	NormalizeWhitespace bool
+	// Matcher decides which placeholders match a key. If nil, the placeholders must match exactly
+	// or after normalizing their whitespace if NormalizeWhitespace is set.
+	Matcher Matcher
+	// RemoveEmptyRuns enables removing the runs whose text was entirely cut while replacing placeholders
+	// which span multiple runs. Runs which were empty before or which contain more than their properties and
+	// the text (e.g. a <w:tab/>) are kept.
+	RemoveEmptyRuns bool
+
+	// delimiters enclose the placeholders, they are set by the Document
+	delimiters delimiters
 }
 
 // NewReplacer returns a new Replacer.
@@ -29,6 +48,7 @@ func NewReplacer(docBytes []byte, placeholder []*Placeholder) *Replacer {
 		document:     docBytes,
 		placeholders: placeholder,
 		ReplaceCount: 0,
+		delimiters:   defaultDelimiters,
 	}
 	r.distinctRuns = r.getDistinctRuns(placeholder)
 
@@ -40,33 +60,28 @@ func NewReplacer(docBytes []byte, placeholder []*Placeholder) *Replacer {
 func (r *Replacer) Replace(placeholderKey string, value string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if !strings.ContainsRune(placeholderKey, OpenDelimiter) ||
-		!strings.ContainsRune(placeholderKey, CloseDelimiter) {
-		placeholderKey = AddPlaceholderDelimiter(placeholderKey)
-	}
+	placeholderKey = r.delimiters.key(placeholderKey)
 
 	// find all occurrences of the placeholderKey inside r.placeholders
 	found := false
 	for i := 0; i < len(r.placeholders); i++ {
 		placeholder := r.placeholders[i]
 
-		if placeholder.Text(r.document) == placeholderKey {
-			found = true
-
-			// ensure html escaping of special chars
-			// reassign to prevent overwriting the actual value which would cause multiple-escapes
-			tmpVal := html.EscapeString(value)
-
-			// replace text of the placeholder'str first fragment with the actual value
-			r.replaceFragmentValue(placeholder.Fragments[0], tmpVal)
+		// the text of a replaced placeholder is its value, which must not be matched again
+		if placeholder.replaced {
+			continue
+		}
 
-			// the other fragments of the placeholder are cut, leaving only the value inside the document.
-			for i := 1; i < len(placeholder.Fragments); i++ {
-				r.cutFragment(placeholder.Fragments[i])
+		if r.matches(placeholder.Text(r.document), placeholderKey) {
+			found = true
+			if err := r.replacePlaceholder(placeholder, value); err != nil {
+				return err
 			}
 		}
 	}
 
+	r.removeEmptiedRuns()
+
 	// all replacing actions might potentially screw up the XML structure
 	// in order to capture this, all tags are re-validated after replacing a value
 	if err := ValidatePositions(r.document, r.distinctRuns); err != nil {
@@ -79,23 +94,206 @@ func (r *Replacer) Replace(placeholderKey string, value string) error {
 	return nil
 }
 
+// ReplaceMap replaces all keys of the PlaceholderMap and returns how many placeholders were replaced per key.
+// Keys without any placeholder are not an error, their count is zero. The values are formatted with fmt.Sprint.
+// The keys are replaced in sorted order and the result is validated once at the end, thus it is much faster
+// than calling Replace for every key of large maps.
+func (r *Replacer) ReplaceMap(placeholderMap PlaceholderMap) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, len(placeholderMap))
+	for key := range placeholderMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// the text of a placeholder does not change until it is replaced, thus it is only assembled once
+	texts := make(map[*Placeholder]string, len(r.placeholders))
+	byText := make(map[string][]*Placeholder)
+	for _, placeholder := range r.placeholders {
+		if !placeholder.replaced {
+			text := placeholder.Text(r.document)
+			texts[placeholder] = text
+			byText[text] = append(byText[text], placeholder)
+		}
+	}
+	exact := r.Matcher == nil && !r.NormalizeWhitespace
+
+	counts := make(map[string]int, len(keys))
+	for _, key := range keys {
+		placeholderKey := r.delimiters.key(key)
+		candidates := r.placeholders
+		if exact {
+			candidates = byText[placeholderKey]
+		}
+
+		value := fmt.Sprint(placeholderMap[key])
+		counts[key] = 0
+		for _, placeholder := range candidates {
+			if placeholder.replaced || !r.matches(texts[placeholder], placeholderKey) {
+				continue
+			}
+			if err := r.replacePlaceholder(placeholder, value); err != nil {
+				return counts, fmt.Errorf("unable to replace %s: %w", key, err)
+			}
+			counts[key]++
+		}
+	}
+
+	r.removeEmptiedRuns()
+	if err := ValidatePositions(r.document, r.distinctRuns); err != nil {
+		return counts, fmt.Errorf("replace produced invalid result: %w", err)
+	}
+	return counts, nil
+}
+
+// ReplaceRemaining replaces all placeholders which have not been replaced yet with the given value.
+// It returns the amount of replaced placeholders.
+func (r *Replacer) ReplaceRemaining(value string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replaced := 0
+	for _, placeholder := range r.placeholders {
+		if placeholder.replaced {
+			continue
+		}
+		if err := r.replacePlaceholder(placeholder, value); err != nil {
+			return replaced, err
+		}
+		replaced++
+	}
+
+	r.removeEmptiedRuns()
+	if err := ValidatePositions(r.document, r.distinctRuns); err != nil {
+		return replaced, fmt.Errorf("replace produced invalid result: %w", err)
+	}
+	return replaced, nil
+}
+
+// InsertBefore inserts the text right in front of all occurrences of the placeholderKey, keeping the placeholder.
+// The text is inserted into the run of the first fragment of the placeholder, thus it has the same properties.
+func (r *Replacer) InsertBefore(placeholderKey, text string) error {
+	return r.insert(placeholderKey, text, true)
+}
+
+// InsertAfter inserts the text right after all occurrences of the placeholderKey, keeping the placeholder.
+// The text is inserted into the run of the last fragment of the placeholder, thus it has the same properties.
+func (r *Replacer) InsertAfter(placeholderKey, text string) error {
+	return r.insert(placeholderKey, text, false)
+}
+
+// insert adds the text in front of or after all placeholders matching the placeholderKey.
+func (r *Replacer) insert(placeholderKey, text string, before bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	placeholderKey = r.delimiters.key(placeholderKey)
+
+	escaped := html.EscapeString(text)
+	deltaLength := int64(len(escaped))
+
+	found := false
+	for _, placeholder := range r.placeholders {
+		if placeholder.replaced || !r.matches(placeholder.Text(r.document), placeholderKey) {
+			continue
+		}
+		found = true
+
+		fragment := placeholder.Fragments[len(placeholder.Fragments)-1]
+		insertPos := fragment.Run.Text.OpenTag.End + fragment.Position.End
+		if before {
+			fragment = placeholder.Fragments[0]
+			insertPos = fragment.Run.Text.OpenTag.End + fragment.Position.Start
+		}
+		r.document = spliceBytes(r.document, insertPos, insertPos, []byte(escaped))
+
+		// the run of the fragment grows, all fragments behind the inserted text need to be shifted.
+		// ShiftReplace treats the inserted text as part of the fragment, which is corrected afterwards.
+		fragment.ShiftReplace(deltaLength)
+		r.shiftFollowingFragments(fragment, deltaLength)
+		if before {
+			fragment.Position.Start += deltaLength
+		} else {
+			fragment.Position.End -= deltaLength
+		}
+		r.BytesChanged += deltaLength
+	}
+
+	if err := ValidatePositions(r.document, r.distinctRuns); err != nil {
+		return fmt.Errorf("insert produced invalid result: %w", err)
+	}
+	if !found {
+		return ErrPlaceholderNotFound
+	}
+	return nil
+}
+
+// replacePlaceholder replaces the text of the placeholder with the value and marks it as replaced.
+// Every fragment is checked before the document is modified, so that a corrupted offset can never
+// remove markup such as the section properties (<w:sectPr>) which follow the text.
+func (r *Replacer) replacePlaceholder(placeholder *Placeholder, value string) error {
+	for _, fragment := range placeholder.Fragments {
+		if err := r.checkCut(fragment); err != nil {
+			return err
+		}
+	}
+
+	// ensure html escaping of special chars
+	// reassign to prevent overwriting the actual value which would cause multiple-escapes
+	tmpVal := html.EscapeString(value)
+
+	// replace text of the placeholder'str first fragment with the actual value
+	r.replaceFragmentValue(placeholder.Fragments[0], tmpVal)
+
+	// the other fragments of the placeholder are cut, leaving only the value inside the document.
+	for i := 1; i < len(placeholder.Fragments); i++ {
+		r.cutFragment(placeholder.Fragments[i])
+	}
+	placeholder.replaced = true
+	return nil
+}
+
+// checkCut returns ErrUnsafeCut if the bytes of the fragment are not entirely inside the text of its run.
+// Text never contains markup as it is escaped, thus any '<' inside the cut means the offsets are off.
+func (r *Replacer) checkCut(fragment *PlaceholderFragment) error {
+	if fragment.Run == nil {
+		return fmt.Errorf("%w: fragment %d has no run", ErrUnsafeCut, fragment.ID)
+	}
+	cutStart := fragment.Run.Text.OpenTag.End + fragment.Position.Start
+	cutEnd := fragment.Run.Text.OpenTag.End + fragment.Position.End
+	if cutStart < fragment.Run.Text.OpenTag.End || cutEnd > fragment.Run.Text.CloseTag.Start {
+		return fmt.Errorf("%w: fragment %d [%d:%d] exceeds its text", ErrUnsafeCut, fragment.ID, cutStart, cutEnd)
+	}
+	cut, ok := safeSlice(r.document, cutStart, cutEnd)
+	if !ok {
+		return fmt.Errorf("%w: fragment %d [%d:%d] is out of bounds", ErrUnsafeCut, fragment.ID, cutStart, cutEnd)
+	}
+	if bytes.IndexByte(cut, '<') >= 0 {
+		return fmt.Errorf("%w: fragment %d [%d:%d] contains markup", ErrUnsafeCut, fragment.ID, cutStart, cutEnd)
+	}
+	return nil
+}
+
+// matches returns true if the text of a placeholder matches the placeholderKey.
+func (r *Replacer) matches(placeholderText, placeholderKey string) bool {
+	matcher := effectiveMatcher(r.Matcher, r.NormalizeWhitespace)
+	return matcher.Match(r.delimiters.withDefaults(placeholderText), r.delimiters.withDefaults(placeholderKey))
+}
+
 // replaceFragmentValue will replace the fragment text with the given value, adjusting all following
 // fragments afterwards.
 func (r *Replacer) replaceFragmentValue(fragment *PlaceholderFragment, value string) {
 	var deltaLength int64
 
-	docBytes := r.document
 	valueLength := int64(len(value))
 	fragLength := fragment.EndPos() - fragment.StartPos()
 	deltaLength = valueLength - fragLength
 
-	// cut out the fragment text literal
+	// replace the fragment text literal with the value
 	cutStart := fragment.Run.Text.OpenTag.End + fragment.Position.Start
 	cutEnd := fragment.Run.Text.OpenTag.End + fragment.Position.End
-	docBytes = append(docBytes[:cutStart], docBytes[cutEnd:]...)
-
-	// insert the value from the cut start position
-	docBytes = append(docBytes[:cutStart], append([]byte(value), docBytes[cutStart:]...)...)
+	docBytes := spliceBytes(r.document, cutStart, cutEnd, []byte(value))
 
 	// shift everything which is after the replaced value for this fragment
 	fragment.ShiftReplace(deltaLength)
@@ -110,17 +308,15 @@ func (r *Replacer) replaceFragmentValue(fragment *PlaceholderFragment, value str
 func (r *Replacer) shiftFollowingFragments(fromFragment *PlaceholderFragment, deltaLength int64) {
 	// handle all fragments which share a run with the given fragment.
 	// this happens for example if there are multiple placeholders in the same line.
-	sharedRunFragments := r.fragmentsInRun(fromFragment.Run)
-	for _, frag := range sharedRunFragments {
-		if frag == fromFragment {
+	for _, fragment := range r.fragmentsInRun(fromFragment.Run) {
+		if fragment == fromFragment {
 			continue // ignore the fromFragment. It is expected to be correct already.
 		}
-		fragment := frag
 
 		// If fromFragment is actually after the fragment there is nothing to do as the bytes
 		// did not shift for those.
 		// Example: (fromFragment == {foo}): {key}{key}{foo}
-		if fromFragment.Position.Start > frag.Position.Start {
+		if fromFragment.Position.Start > fragment.Position.Start {
 			continue
 		}
 
@@ -129,45 +325,9 @@ func (r *Replacer) shiftFollowingFragments(fromFragment *PlaceholderFragment, de
 		fragment.Position.End += deltaLength
 	}
 
-	// find all fragments which do not share a run with fromFragment
-	followingFragments := r.fragmentsFromPosition(fromFragment.Run.Text.OpenTag.End)
-
-	// remove fragments which have been adjusted already above
-	for i, fragment := range followingFragments {
-		alreadyHandled := func(fragment *PlaceholderFragment) bool {
-			for _, runFragment := range sharedRunFragments {
-				if fragment == runFragment {
-					return true
-				}
-			}
-			return false
-		}
-		if alreadyHandled(fragment) {
-			followingFragments = append(followingFragments[:i], followingFragments[i+1:]...)
-		}
-	}
-
-	// we need to keep track of which runs were already modified.
-	// This is important since there may be following fragments which share a run
-	var modifiedRuns []*Run
-	isAlreadyModified := func(r *Run) bool {
-		for _, run := range modifiedRuns {
-			if run == r {
-				return true
-			}
-		}
-		return false
-	}
-
-	// shift all fragments which follow 'fromFragment' and are in a different Run
-	for _, frag := range followingFragments {
-		if isAlreadyModified(frag.Run) {
-			continue
-		}
-		followingFragment := frag
-		followingFragment.ShiftAll(deltaLength)
-		modifiedRuns = append(modifiedRuns, followingFragment.Run)
-	}
+	// all other runs which follow the run of fromFragment are shifted as a whole, every run only once.
+	// The run of fromFragment starts in front of its text and is thus never shifted.
+	DocumentRuns(r.distinctRuns).ShiftFrom(fromFragment.Run.Text.OpenTag.End, deltaLength)
 }
 
 // curFragment will remove the fragment text from the document bytes.
@@ -179,72 +339,79 @@ func (r *Replacer) cutFragment(fragment *PlaceholderFragment) {
 	cutLength := fragment.Position.End - fragment.Position.Start
 
 	// cut fragment from document and adjust positions
-	docBytes = append(docBytes[:cutStart], docBytes[cutEnd:]...)
+	docBytes = spliceBytes(docBytes, cutStart, cutEnd, nil)
 	fragment.ShiftCut(cutLength)
 
 	r.document = docBytes
 	r.BytesChanged -= cutLength
 	r.shiftFollowingFragments(fragment, -cutLength)
 
+	if r.RemoveEmptyRuns && cutLength > 0 && fragment.Run.Text.OpenTag.End == fragment.Run.Text.CloseTag.Start {
+		r.emptiedRuns = append(r.emptiedRuns, fragment.Run)
+	}
 }
 
-// fragmentsFromPosition will return all fragments where: fragment.Run.OpenTag.Start > startingFrom
-func (r *Replacer) fragmentsFromPosition(startingFrom int64) (found []*PlaceholderFragment) {
-	for _, placeholder := range r.placeholders {
-		for _, fragment := range placeholder.Fragments {
-			if fragment.Run.OpenTag.Start >= startingFrom {
-				found = append(found, fragment)
-				continue
+// removeEmptiedRuns removes the runs which were emptied by cutFragment from the document.
+// Only runs which consist of nothing but their properties and the text are removed.
+func (r *Replacer) removeEmptiedRuns() {
+	for _, run := range r.emptiedRuns {
+		if _, ok := mergeableRunProperties(r.document, run); !ok || run.Text.OpenTag.End != run.Text.CloseTag.Start {
+			continue
+		}
+		length := run.CloseTag.End - run.OpenTag.Start
+		r.document = spliceBytes(r.document, run.OpenTag.Start, run.CloseTag.End, nil)
+		r.BytesChanged -= length
+
+		// the removed run is no longer part of the document and thus not validated anymore.
+		// Its fragments are all cut, their text stays empty.
+		for i, distinct := range r.distinctRuns {
+			if distinct == run {
+				r.distinctRuns = append(r.distinctRuns[:i], r.distinctRuns[i+1:]...)
+				break
 			}
 		}
+		DocumentRuns(r.distinctRuns).ShiftFrom(run.CloseTag.End, -length)
 	}
-	return found
+	r.emptiedRuns = nil
 }
 
-// fragmentsInRun returns all fragments which are in the given Run.
-func (r *Replacer) fragmentsInRun(run *Run) (fragments []*PlaceholderFragment) {
-	for _, placeholder := range r.placeholdersInRun(run) {
-		for _, fragment := range placeholder.Fragments {
-			if fragment.Run == run {
-				fragments = append(fragments, fragment)
-			}
-		}
+// spliceBytes replaces b[start:end] with the value in place and returns the resulting slice.
+// Only the bytes behind end are moved, the slice grows like append if the value is longer than the replaced bytes.
+func spliceBytes(b []byte, start, end int64, value []byte) []byte {
+	delta := int64(len(value)) - (end - start)
+	size := int64(len(b)) + delta
+	if delta > 0 {
+		b = append(b, make([]byte, delta)...)
 	}
-	return fragments
+	copy(b[start+int64(len(value)):], b[end:])
+	copy(b[start:], value)
+	return b[:size]
 }
 
-// placeholdersInRun returns all placeholders which belong to the given Run
-func (r *Replacer) placeholdersInRun(run *Run) (p []*Placeholder) {
-	for _, placeholder := range r.placeholders {
-		for _, fragment := range placeholder.Fragments {
-			if fragment.Run == run {
-				p = append(p, placeholder)
-				continue
+// fragmentsInRun returns all fragments which are in the given Run.
+// The fragments of all runs are indexed on the first call, since the fragments of the placeholders never change.
+func (r *Replacer) fragmentsInRun(run *Run) []*PlaceholderFragment {
+	if r.runFragments == nil {
+		r.runFragments = make(map[*Run][]*PlaceholderFragment)
+		for _, placeholder := range r.placeholders {
+			for _, fragment := range placeholder.Fragments {
+				r.runFragments[fragment.Run] = append(r.runFragments[fragment.Run], fragment)
 			}
 		}
 	}
-	return p
+	return r.runFragments[run]
 }
 
 // getDistinctRuns iterates over the given placeholders and returns a slice of runs which contains
 // every run only once.
 func (r *Replacer) getDistinctRuns(placeholder []*Placeholder) []*Run {
-	var seenRuns []int
-	seen := func(runID int) bool {
-		for _, id := range seenRuns {
-			if runId == id {
-				return true
-			}
-		}
-		return false
-	}
-
+	seen := make(map[*Run]bool)
 	var runs []*Run
 	for _, placeholder := range placeholder {
 		for _, fragment := range placeholder.Fragments {
-			if !seen(fragment.Run.ID) {
+			if !seen[fragment.Run] {
 				runs = append(runs, fragment.Run)
-				seenRuns = append(seenRuns, fragment.Run.ID)
+				seen[fragment.Run] = true
 			}
 		}
 	}
@@ -1,8 +1,13 @@
 package docx
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -55,3 +60,390 @@ func TestReplacer_Replace(t *testing.T) {
 	// cleanup
 	_ = os.Remove("./test/out.docx")
 }
+
+func TestDocument_NormalizeWhitespace(t *testing.T) {
+	body := `<w:p><w:r><w:t>{foo` + "\u00a0" + `}</w:t></w:r><w:r><w:t xml:space="preserve"> and </w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve">{ bar` + "\u200b" + `</w:t></w:r><w:r><w:t>}</w:t></w:r></w:p>`
+	docx := newTestDocx(t, map[string]string{DocumentXml: newTestDocumentXml(body)})
+	placeholderMap := PlaceholderMap{"foo": "FOO", "bar": "BAR"}
+
+	// placeholders must match exactly by default
+	doc, err := OpenBytes(docx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "{foo\u00a0} and { bar\u200b}" {
+		t.Errorf("placeholders were replaced without normalization, have=%s", text)
+	}
+
+	doc, err = OpenBytes(docx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	doc.SetNormalizeWhitespace(true)
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		t.Error("replacing failed", err)
+		return
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "FOO and BAR" {
+		t.Errorf("unexpected text, want=%s, have=%s", "FOO and BAR", text)
+	}
+}
+
+func TestDocument_ReplaceAll_Runs(t *testing.T) {
+	run := func(text string) string {
+		return `<w:r><w:rPr><w:noProof/><w:lang w:val="de-DE"/></w:rPr><w:t xml:space="preserve">` + text + `</w:t></w:r>`
+	}
+	boldRun := func(text string) string {
+		return `<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">` + text + `</w:t></w:r>`
+	}
+
+	tests := []struct {
+		name            string
+		body            string
+		removeEmptyRuns bool
+		expected        string
+	}{
+		{
+			// the properties of all runs are kept
+			name: "keeps run properties",
+			body: `<w:p>` + run("Hello {na") + run("m") + run("e}, {gre") + run("eting} and {name}") + `</w:p>` +
+				`<w:p>` + run("{gre") + run("eting}") + `</w:p>`,
+			expected: `<w:p>` + run("Hello John Doe") + run("") + run(", Hi") + run(" and John Doe") + `</w:p>` +
+				`<w:p>` + run("Hi") + run("") + `</w:p>`,
+		},
+		{
+			// the empty run of the template and the run containing a tab must be kept
+			name: "remove empty runs",
+			body: `<w:p>` + boldRun("") + boldRun("Hello {na") + boldRun("m") + boldRun("e}, {gre") +
+				`<w:r><w:t>et</w:t><w:tab/></w:r>` + boldRun("ing}") + `</w:p>`,
+			removeEmptyRuns: true,
+			expected: `<w:p>` + boldRun("") + boldRun("Hello John Doe") + boldRun(", Hi") +
+				`<w:r><w:t></w:t><w:tab/></w:r>` + `</w:p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, tt.body)
+			doc.SetRemoveEmptyRuns(tt.removeEmptyRuns)
+
+			// the settings must be kept by clones
+			for _, doc := range []*Document{doc, doc.Clone()} {
+				if err := doc.ReplaceAll(PlaceholderMap{"name": "John Doe", "greeting": "Hi"}); err != nil {
+					t.Fatal("replacing failed", err)
+				}
+				if data := string(writtenFile(t, doc, DocumentXml)); data != newTestDocumentXml(tt.expected) {
+					t.Errorf("unexpected document after replacing, want=%s, have=%s", newTestDocumentXml(tt.expected), data)
+				}
+			}
+		})
+	}
+}
+
+func TestReplacer_DistinctRuns(t *testing.T) {
+	body := `<w:p><w:r><w:t>{foo}{bar}{ba</w:t></w:r><w:r><w:t>z}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	if runs := doc.fileReplacers[DocumentXml].distinctRuns; len(runs) != 2 {
+		t.Errorf("unexpected amount of distinct runs, want=%d, have=%d", 2, len(runs))
+	}
+}
+
+func TestReplacer_EmptyInput(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"nil":   nil,
+		"empty": []byte(`<w:body/>`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			parser := NewRunParser(data)
+			if err := parser.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			placeholders, err := ParsePlaceholders(parser.Runs(), data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(placeholders) != 0 {
+				t.Errorf("unexpected number of placeholders, want=%d, have=%d", 0, len(placeholders))
+			}
+
+			replacer := NewReplacer(data, placeholders)
+			if err := replacer.Replace("foo", "bar"); !errors.Is(err, ErrPlaceholderNotFound) {
+				t.Errorf("unexpected error, want=%v, have=%v", ErrPlaceholderNotFound, err)
+			}
+			if !bytes.Equal(replacer.Bytes(), data) {
+				t.Error("expected the data to be unchanged")
+			}
+		})
+	}
+}
+
+func TestDocument_SetMissingPlaceholderValue(t *testing.T) {
+	body := `<w:p><w:r><w:t>{name} lives in {ci</w:t></w:r><w:r><w:t>ty}, {country}.</w:t></w:r></w:p>`
+
+	tests := []struct {
+		name     string
+		value    *string
+		expected string
+	}{
+		{name: "unset keeps placeholders", expected: "Alice lives in {city}, {country}."},
+		{name: "default value", value: stringPointer("N/A"), expected: "Alice lives in N/A, N/A."},
+		{name: "empty value", value: stringPointer(""), expected: "Alice lives in , ."},
+		{name: "value with delimiters", value: stringPointer("{?}"), expected: "Alice lives in {?}, {?}."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, body)
+			if tt.value != nil {
+				doc.SetMissingPlaceholderValue(*tt.value)
+			}
+			if err := doc.ReplaceAll(PlaceholderMap{"name": "Alice"}); err != nil {
+				t.Fatal(err)
+			}
+			if text := writtenText(t, doc, DocumentXml); text != tt.expected {
+				t.Errorf("unexpected text, want=%s, have=%s", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestDocument_ReplacePartial(t *testing.T) {
+	body := `<w:p><w:r><w:t>{name} lives in {ci</w:t></w:r><w:r><w:t>ty}, {country}.</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	// the missing value is only applied by ReplaceAll, the remaining placeholders are kept for the next stage
+	doc.SetMissingPlaceholderValue("N/A")
+	doc.SetEscapeDelimiters(true)
+
+	// values containing delimiters must not turn into placeholders of the next stage
+	if err := doc.ReplacePartial(PlaceholderMap{"name": "{country}", "key": "footer"}); err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, placeholder := range doc.Placeholders() {
+		remaining = append(remaining, placeholder.Text(doc.GetFile(DocumentXml)))
+	}
+	if expected := []string{"{city}", "{country}"}; !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("unexpected remaining placeholders, want=%q, have=%q", expected, remaining)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "{country} lives in {city}, {country}." {
+		t.Errorf("unexpected text after the first stage, want=%q, have=%q", "{country} lives in {city}, {country}.", text)
+	}
+
+	if err := doc.ReplacePartial(PlaceholderMap{"city": "Berlin", "country": "Germany"}); err != nil {
+		t.Fatal(err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "{country} lives in Berlin, Germany." {
+		t.Errorf("unexpected text after the second stage, want=%q, have=%q", "{country} lives in Berlin, Germany.", text)
+	}
+	if placeholders := doc.Placeholders(); len(placeholders) != 0 {
+		t.Errorf("not all placeholders were replaced, want=%d, have=%d", 0, len(placeholders))
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func TestReplacer_ReplaceMap(t *testing.T) {
+	data := []byte(`<w:p><w:r><w:t>{foo} and {foo}, {ba</w:t></w:r><w:r><w:t>r}</w:t></w:r></w:p>`)
+	parser := NewRunParser(data)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replacer := NewReplacer(data, placeholders)
+	counts, err := replacer.ReplaceMap(PlaceholderMap{"foo": 1, "bar": "two", "unknown": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"foo": 2, "bar": 1, "unknown": 0}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("unexpected counts, want=%v, have=%v", expected, counts)
+	}
+	if !bytes.Contains(replacer.Bytes(), []byte("1 and 1, two")) {
+		t.Errorf("unexpected result, have=%s", replacer.Bytes())
+	}
+}
+
+func TestDocument_SetStrictCount(t *testing.T) {
+	// the parser only knows one text-run per run, thus the first {foo} cannot be replaced
+	body := `<w:p><w:r><w:t>{foo}</w:t><w:tab/><w:t>{foo}</w:t></w:r></w:p>`
+	t.Run("strict by default", func(t *testing.T) {
+		doc := openTestDocument(t, body)
+		if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err == nil {
+			t.Error("expected an error")
+		}
+		if mismatches := doc.CountMismatches(); len(mismatches) != 0 {
+			t.Errorf("unexpected mismatches, have=%v", mismatches)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		doc := openTestDocument(t, body)
+		doc.SetStrictCount(false)
+		if err := doc.ReplaceAll(PlaceholderMap{"foo": "bar"}); err != nil {
+			t.Fatal(err)
+		}
+		if text := writtenText(t, doc, DocumentXml); text != "{foo}bar" {
+			t.Errorf("unexpected text, want=%q, have=%q", "{foo}bar", text)
+		}
+		expected := []CountMismatch{{File: DocumentXml, Expected: 2, Replaced: 1}}
+		if mismatches := doc.CountMismatches(); !reflect.DeepEqual(mismatches, expected) {
+			t.Errorf("unexpected mismatches, want=%v, have=%v", expected, mismatches)
+		}
+	})
+}
+
+func TestDocument_Replace_Sequential(t *testing.T) {
+	body := `<w:p><w:r><w:t>{a} {b</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>} {c}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{b}{a}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	// every call must continue on the result of the previous one
+	for _, replacement := range [][2]string{{"a", "first"}, {"b", "2"}, {"c", "a much longer third value"}} {
+		if err := doc.Replace(replacement[0], replacement[1]); err != nil {
+			t.Fatalf("replacing %s failed: %s", replacement[0], err)
+		}
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"a": "unused"}); err != nil {
+		t.Fatalf("replacing an already replaced key failed: %s", err)
+	}
+
+	written := writtenFile(t, doc, DocumentXml)
+	if err := xml.Unmarshal(written, new(interface{})); err != nil {
+		t.Fatalf("result is not valid xml: %s", err)
+	}
+	expected := "first 2 a much longer third value2first"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+}
+
+func TestDocument_Replace_AdjacentPlaceholders(t *testing.T) {
+	bodies := map[string]string{
+		"single run": `<w:p><w:r><w:t>{a}{b}{c}{d}</w:t></w:r></w:p>`,
+		"split runs": `<w:p><w:r><w:t>{a}{b</w:t></w:r><w:r><w:t>}{c}{</w:t></w:r><w:r><w:t>d}</w:t></w:r></w:p>`,
+		"with text":  `<w:p><w:r><w:t>x{a}{b}{c}{d}x</w:t></w:r></w:p>`,
+	}
+	values := map[string]map[string]string{
+		"shorter": {"a": "1", "b": "2", "c": "3", "d": "4"},
+		"equal":   {"a": "AAA", "b": "BBB", "c": "CCC", "d": "DDD"},
+		"longer":  {"a": "first value", "b": "second value", "c": "third", "d": "fourth value"},
+		"mixed":   {"a": "", "b": "BBB", "c": "a very long value", "d": "4"},
+		"escaped": {"a": "{b}", "b": "}}", "c": "{{", "d": "{a}{b}"},
+	}
+	// the keys are replaced one by one, in different orders
+	orders := [][]string{{"a", "b", "c", "d"}, {"d", "c", "b", "a"}, {"b", "d", "a", "c"}}
+
+	for bodyName, body := range bodies {
+		for valuesName, placeholderMap := range values {
+			for _, order := range orders {
+				name := fmt.Sprintf("%s/%s/%s", bodyName, valuesName, strings.Join(order, ""))
+				t.Run(name, func(t *testing.T) {
+					doc := openTestDocument(t, body)
+					doc.SetEscapeDelimiters(true)
+					for _, key := range order {
+						if err := doc.Replace(key, placeholderMap[key]); err != nil {
+							t.Fatalf("replacing %s failed: %s", key, err)
+						}
+					}
+
+					expected := placeholderMap["a"] + placeholderMap["b"] + placeholderMap["c"] + placeholderMap["d"]
+					if strings.HasPrefix(body, "<w:p><w:r><w:t>x") {
+						expected = "x" + expected + "x"
+					}
+					// writtenText ensures that the result is valid XML
+					if text := writtenText(t, doc, DocumentXml); text != expected {
+						t.Errorf("unexpected text, want=%q, have=%q", expected, text)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestDocument_SetMaxValueLength(t *testing.T) {
+	body := `<w:p><w:r><w:t>{short} {long}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	doc.SetMaxValueLength(10)
+
+	err := doc.ReplaceAll(PlaceholderMap{"short": "ok", "long": strings.Repeat("x", 11)})
+	if !errors.Is(err, ErrValueTooLong) || !strings.Contains(err.Error(), "long") {
+		t.Errorf("unexpected error, want=%v naming the key, have=%v", ErrValueTooLong, err)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "{short} {long}" {
+		t.Errorf("unexpected replacement, have=%s", text)
+	}
+
+	if err := doc.ReplaceAll(PlaceholderMap{"short": "ok", "long": strings.Repeat("x", 10)}); err != nil {
+		t.Errorf("values within the limit were not replaced, have=%s", err)
+	}
+}
+
+func TestReplacer_Insert(t *testing.T) {
+	data := []byte(`<w:p><w:r><w:t>{a}{b} and {c</w:t></w:r><w:r><w:t>}{a}</w:t></w:r></w:p>`)
+	parser := NewRunParser(data)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacer := NewReplacer(data, placeholders)
+
+	if err := replacer.InsertBefore("a", "<"); err != nil {
+		t.Fatal(err)
+	}
+	if err := replacer.InsertAfter("c", " & more"); err != nil {
+		t.Fatal(err)
+	}
+	if err := replacer.InsertBefore("c", "["); err != nil {
+		t.Fatal(err)
+	}
+	if err := replacer.InsertAfter("unknown", "x"); !errors.Is(err, ErrPlaceholderNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrPlaceholderNotFound, err)
+	}
+
+	// the placeholders are kept and can still be replaced afterwards
+	for key, value := range map[string]string{"a": "A", "b": "B", "c": "C"} {
+		if err := replacer.Replace(key, value); err != nil {
+			t.Fatalf("replacing %s failed: %s", key, err)
+		}
+	}
+	expected := `<w:p><w:r><w:t>&lt;AB and [C</w:t></w:r><w:r><w:t> &amp; more&lt;A</w:t></w:r></w:p>`
+	if string(replacer.Bytes()) != expected {
+		t.Errorf("unexpected result\nwant=%s\nhave=%s", expected, replacer.Bytes())
+	}
+}
+
+func TestDocument_AddTransformer(t *testing.T) {
+	body := `<w:p><w:r><w:t>{name}, {city}, {count}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	doc.AddTransformer("name", strings.TrimSpace)
+	doc.AddTransformer("name", strings.ToUpper)
+	doc.AddTransformer("count", func(value string) string {
+		return value + " items"
+	})
+
+	// clones keep the transformers
+	clone := doc.Clone()
+	for _, doc := range []*Document{doc, clone} {
+		if err := doc.ReplaceAll(PlaceholderMap{"name": "  jane doe ", "city": "berlin", "count": 3}); err != nil {
+			t.Fatal(err)
+		}
+		if text := writtenText(t, doc, DocumentXml); text != "JANE DOE, berlin, 3 items" {
+			t.Errorf("unexpected text, want=%q, have=%q", "JANE DOE, berlin, 3 items", text)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var (
+	// TrackedChangeRegex matches the start of any tracked change (revision) element.
+	TrackedChangeRegex = regexp.MustCompile(`<w:(ins|del|moveFrom|moveTo|rPrChange|pPrChange|sectPrChange|tblPrChange|trPrChange|tcPrChange)\b`)
+
+	// revisionMarkerRegex matches revision markers without content, e.g. <w:ins .../> inside run properties
+	// or the <w:moveFromRangeStart .../> range markers.
+	revisionMarkerRegex = regexp.MustCompile(`<w:(ins|del|moveFrom|moveTo|moveFromRangeStart|moveFromRangeEnd|moveToRangeStart|moveToRangeEnd)\b[^>]*/>`)
+	// removedContentRegex matches deleted and moved-away content including the wrapping element.
+	removedContentRegex = regexp.MustCompile(`(?s)<w:del\b[^>]*>.*?</w:del>|<w:moveFrom\b[^>]*>.*?</w:moveFrom>`)
+	// propertyChangeRegex matches the recorded previous properties of formatting changes.
+	propertyChangeRegex = regexp.MustCompile(`(?s)<w:rPrChange\b[^>]*>.*?</w:rPrChange>|<w:pPrChange\b[^>]*>.*?</w:pPrChange>|` +
+		`<w:sectPrChange\b[^>]*>.*?</w:sectPrChange>|<w:tblPrChange\b[^>]*>.*?</w:tblPrChange>|` +
+		`<w:trPrChange\b[^>]*>.*?</w:trPrChange>|<w:tcPrChange\b[^>]*>.*?</w:tcPrChange>`)
+	// insertedContentTagRegex matches the open and close tags which wrap inserted and moved-here content.
+	insertedContentTagRegex = regexp.MustCompile(`<w:(ins|moveTo)\b[^>]*>|</w:(ins|moveTo)>`)
+
+	// insertedContentRegex matches inserted and moved-here content including the wrapping element.
+	insertedContentRegex = regexp.MustCompile(`(?s)<w:ins\b[^>]*>.*?</w:ins>|<w:moveTo\b[^>]*>.*?</w:moveTo>`)
+	// removedContentTagRegex matches the open and close tags which wrap deleted and moved-away content.
+	removedContentTagRegex = regexp.MustCompile(`<w:(del|moveFrom)\b[^>]*>|</w:(del|moveFrom)>`)
+	// deletedTextRegex matches the open and close tags of deleted text.
+	deletedTextRegex = regexp.MustCompile(`<(/?)w:delText\b`)
+	// deletedInstrTextRegex matches the open and close tags of deleted field instructions.
+	deletedInstrTextRegex = regexp.MustCompile(`<(/?)w:delInstrText\b`)
+	// propertyChangeStartRegex matches the start of a formatting change, the submatch is the name of the properties.
+	propertyChangeStartRegex = regexp.MustCompile(`<w:(rPr|pPr|sectPr|tblPr|trPr|tcPr)Change\b`)
+)
+
+// keptProperties are the elements which are part of the properties but not of their recorded previous state.
+// They are kept when a formatting change is rejected, either in front of or after the previous properties.
+var keptProperties = map[string]struct{ before, after []string }{
+	"w:pPr":    {after: []string{"w:rPr", "w:sectPr"}},
+	"w:sectPr": {before: []string{"w:headerReference", "w:footerReference"}},
+}
+
+// HasTrackedChanges returns true if any of the files contain tracked changes.
+// Tracked changes wrap runs and deleted text still resides in the XML, which can interfere with replacing.
+func (d *Document) HasTrackedChanges() bool {
+	for _, data := range d.files {
+		if TrackedChangeRegex.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// FlattenRevisions accepts or rejects all tracked changes of all files.
+// If accept is true, deleted content is removed, inserted content is unwrapped and recorded formatting changes
+// are dropped. Otherwise inserted content is removed, deleted content is unwrapped and turned back into text
+// and the recorded previous formatting is restored.
+// It should be called before replacing, all files are parsed again afterwards.
+//
+// Note: A deleted or inserted paragraph mark only loses its revision marker, the paragraphs are not merged.
+func (d *Document) FlattenRevisions(accept bool) error {
+	for name, data := range d.files {
+		if !TrackedChangeRegex.Match(data) {
+			continue
+		}
+
+		data = revisionMarkerRegex.ReplaceAll(data, nil)
+		if accept {
+			data = removedContentRegex.ReplaceAll(data, nil)
+			data = propertyChangeRegex.ReplaceAll(data, nil)
+			data = insertedContentTagRegex.ReplaceAll(data, nil)
+		} else {
+			data = insertedContentRegex.ReplaceAll(data, nil)
+			data = removedContentTagRegex.ReplaceAll(data, nil)
+			data = deletedTextRegex.ReplaceAll(data, []byte("<${1}w:t"))
+			data = deletedInstrTextRegex.ReplaceAll(data, []byte("<${1}w:instrText"))
+			data = rejectPropertyChanges(data)
+		}
+
+		d.files[name] = data
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rejectPropertyChanges replaces all properties which record a formatting change with their previous state.
+// Changes which cannot be located inside of their properties are dropped.
+func rejectPropertyChanges(data []byte) []byte {
+	for {
+		loc := propertyChangeStartRegex.FindSubmatchIndex(data)
+		if loc == nil {
+			return data
+		}
+		name := "w:" + string(data[loc[2]:loc[3]])
+
+		var properties []int
+		if behind := lastOpenTag(data[:loc[0]], name); behind >= 0 {
+			start := bytes.LastIndex(data[:behind], []byte("<"+name))
+			if end := leadingElement(name).FindIndex(data[start:]); end != nil && start+end[1] > loc[1] {
+				properties = []int{start, start + end[1]}
+			}
+		}
+		if properties == nil {
+			change := leadingElement(name + "Change").FindIndex(data[loc[0]:])
+			if change == nil {
+				return data
+			}
+			data = append(data[:loc[0]:loc[0]], data[loc[0]+change[1]:]...)
+			continue
+		}
+
+		restored := restoreProperties(name, data[properties[0]:properties[1]])
+		data = append(data[:properties[0]:properties[0]], append(restored, data[properties[1]:]...)...)
+	}
+}
+
+// restoreProperties returns the properties with the previous state recorded in their change element.
+func restoreProperties(name string, properties []byte) []byte {
+	kept := keptProperties[name]
+	var before, previous, after [][]byte
+	for _, child := range childElements(elementContent(properties)) {
+		switch childName := elementName(child); {
+		case childName == name+"Change":
+			previous = childElements(elementContent(leadingElement(name).Find(elementContent(child))))
+		case containsName(kept.before, childName):
+			before = append(before, child)
+		case containsName(kept.after, childName):
+			after = append(after, child)
+		}
+	}
+
+	restored := append([]byte{}, elementTagRegex.Find(properties)...)
+	for _, children := range [][][]byte{before, previous, after} {
+		for _, child := range children {
+			restored = append(restored, child...)
+		}
+	}
+	return append(restored, "</"+name+">"...)
+}
+
+// childElements splits the content of an element into its child elements.
+func childElements(content []byte) [][]byte {
+	var children [][]byte
+	for {
+		tag := elementTagRegex.FindSubmatchIndex(content)
+		if tag == nil {
+			return children
+		}
+		loc := leadingElement(content[tag[4]:tag[5]]).FindIndex(content)
+		if loc == nil {
+			return children
+		}
+		children = append(children, content[:loc[1]])
+		content = content[loc[1]:]
+	}
+}
+
+// elementContent returns everything between the open and the close tag of an element.
+func elementContent(element []byte) []byte {
+	tag := elementTagRegex.FindSubmatchIndex(element)
+	if tag == nil || tag[7] > tag[6] {
+		return nil
+	}
+	return element[tag[1]:bytes.LastIndexByte(element, '<')]
+}
+
+// elementName returns the name of an element.
+func elementName(element []byte) string {
+	if tag := elementTagRegex.FindSubmatch(element); tag != nil {
+		return string(tag[2])
+	}
+	return ""
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
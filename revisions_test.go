@@ -0,0 +1,84 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_FlattenRevisions(t *testing.T) {
+	body := `<w:p>` +
+		`<w:pPr><w:jc w:val="center"/><w:rPr><w:ins w:id="4" w:author="foo"/></w:rPr>` +
+		`<w:pPrChange w:id="5" w:author="foo"><w:pPr><w:jc w:val="left"/></w:pPr></w:pPrChange></w:pPr>` +
+		`<w:r><w:t xml:space="preserve">Dear </w:t></w:r>` +
+		`<w:del w:id="1" w:author="foo" w:date="2021-01-01T00:00:00Z"><w:r><w:delText>{old}</w:delText></w:r></w:del>` +
+		`<w:ins w:id="2" w:author="foo" w:date="2021-01-01T00:00:00Z"><w:r><w:rPr><w:b/><w:rPrChange w:id="3" w:author="foo"><w:rPr/></w:rPrChange></w:rPr><w:t>{name}</w:t></w:r></w:ins>` +
+		`<w:r><w:rPr><w:i/><w:rPrChange w:id="6" w:author="foo"><w:rPr><w:u w:val="single"/></w:rPr></w:rPrChange></w:rPr><w:t>!</w:t></w:r>` +
+		`</w:p>`
+
+	tests := []struct {
+		name     string
+		accept   bool
+		text     string
+		contains []string
+		missing  []string
+	}{
+		{
+			name:     "accept",
+			accept:   true,
+			text:     "Dear John!",
+			contains: []string{`<w:jc w:val="center"/><w:rPr></w:rPr></w:pPr>`, `<w:rPr><w:b/></w:rPr>`, `<w:rPr><w:i/></w:rPr>`},
+			missing:  []string{"{old}", `<w:u `},
+		},
+		{
+			name:     "reject",
+			accept:   false,
+			text:     "Dear Jane!",
+			contains: []string{`<w:pPr><w:jc w:val="left"/><w:rPr></w:rPr></w:pPr>`, `<w:r><w:t>Jane</w:t></w:r>`, `<w:rPr><w:u w:val="single"/></w:rPr>`},
+			missing:  []string{"{name}", "John", `<w:b/>`, `<w:i/>`, "delText"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := openTestDocument(t, body)
+			if !doc.HasTrackedChanges() {
+				t.Fatal("expected document to have tracked changes")
+			}
+			if err := doc.FlattenRevisions(tt.accept); err != nil {
+				t.Fatal("flattening revisions failed", err)
+			}
+			if doc.HasTrackedChanges() {
+				t.Error("expected all tracked changes to be removed")
+			}
+
+			if err := doc.ReplaceAll(PlaceholderMap{"name": "John", "old": "Jane"}); err != nil {
+				t.Fatal("replacing failed", err)
+			}
+			if text := writtenText(t, doc, DocumentXml); text != tt.text {
+				t.Errorf("unexpected text, want=%s, have=%s", tt.text, text)
+			}
+
+			data := string(writtenFile(t, doc, DocumentXml))
+			for _, s := range tt.contains {
+				if !strings.Contains(data, s) {
+					t.Errorf("expected %s in the document, have=%s", s, data)
+				}
+			}
+			for _, s := range tt.missing {
+				if strings.Contains(data, s) {
+					t.Errorf("unexpected %s in the document, have=%s", s, data)
+				}
+			}
+		})
+	}
+}
+
+func TestRejectPropertyChanges_SectionProperties(t *testing.T) {
+	data := []byte(`<w:sectPr w:rsidR="1"><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="11906"/>` +
+		`<w:sectPrChange w:id="1" w:author="foo"><w:sectPr><w:pgSz w:w="12240"/><w:cols/></w:sectPr></w:sectPrChange></w:sectPr>`)
+
+	expected := `<w:sectPr w:rsidR="1"><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="12240"/><w:cols/></w:sectPr>`
+	if have := string(rejectPropertyChanges(data)); have != expected {
+		t.Errorf("unexpected section properties, want=%s, have=%s", expected, have)
+	}
+}
@@ -1,9 +1,17 @@
 package docx
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sync/atomic"
+)
 
 var (
-	runId = 0 // global Run ID counter. Incremented by NewRun()
+	runId int64 = 0 // global Run ID counter. Incremented by NewRunID()
+
+	// ErrRunWithoutText is returned if the text of a run without a <w:t> element is set.
+	ErrRunWithoutText = errors.New("run does not have a text")
 )
 
 // TagPair describes an opening and closing tag position.
@@ -35,34 +43,56 @@ func (r *Run) GetText(documentBytes []byte) string {
 	if !r.HasText {
 		return ""
 	}
-	startPos := r.Text.OpenTag.End
-	endPos := r.Text.CloseTag.Start
+	text, _ := safeSlice(documentBytes, r.Text.OpenTag.End, r.Text.CloseTag.Start)
+	return string(text)
+}
 
-	if int64(len(documentBytes)) < startPos || int64(len(documentBytes)) < endPos {
-		return ""
+// SetText replaces the whole text of the run with newText, which is escaped, and returns the updated bytes
+// together with the delta in length. The positions of the run itself are adjusted, all runs following
+// it must be shifted by the delta by the caller, e.g. using DocumentRuns.ShiftFrom.
+// The given doc may be modified.
+func (r *Run) SetText(doc []byte, newText string) ([]byte, int64, error) {
+	if !r.HasText {
+		return doc, 0, fmt.Errorf("%w: run %d", ErrRunWithoutText, r.ID)
+	}
+	if _, ok := safeSlice(doc, r.Text.OpenTag.End, r.Text.CloseTag.Start); !ok {
+		return doc, 0, fmt.Errorf("text of run %d is out of bounds", r.ID)
 	}
 
-	return string(documentBytes[startPos:endPos])
+	value := []byte(html.EscapeString(newText))
+	delta := int64(len(value)) - (r.Text.CloseTag.Start - r.Text.OpenTag.End)
+	doc = append(doc[:r.Text.OpenTag.End], append(value, doc[r.Text.CloseTag.Start:]...)...)
+
+	r.Text.CloseTag.Start += delta
+	r.Text.CloseTag.End += delta
+	r.CloseTag.Start += delta
+	r.CloseTag.End += delta
+	return doc, delta, nil
 }
 
 // String returns a string representation of the run, given the source bytes.
-// It may be helpful in debugging.
+// It may be helpful in debugging. Positions outside of the given bytes are printed with an empty text.
 func (r *Run) String(bytes []byte) string {
 	format := "run %d from offset [%d:%d] '%s' to [%d:%d] '%s'; run-text offset from [%d:%d] '%s' to [%d:%d] '%s'"
 	formatNoText := "run %d from offset [%d:%d] '%s' to [%d:%d] '%s'"
 
+	text := func(p Position) []byte {
+		b, _ := safeSlice(bytes, p.Start, p.End)
+		return b
+	}
+
 	if !r.HasText {
 		return fmt.Sprintf(formatNoText, r.ID,
-			r.OpenTag.Start, r.OpenTag.End, bytes[r.OpenTag.Start:r.OpenTag.End],
-			r.CloseTag.Start, r.CloseTag.End, bytes[r.CloseTag.Start:r.CloseTag.End],
+			r.OpenTag.Start, r.OpenTag.End, text(r.OpenTag),
+			r.CloseTag.Start, r.CloseTag.End, text(r.CloseTag),
 		)
 	}
 
 	return fmt.Sprintf(format, r.ID,
-		r.OpenTag.Start, r.OpenTag.End, bytes[r.OpenTag.Start:r.OpenTag.End],
-		r.CloseTag.Start, r.CloseTag.End, bytes[r.CloseTag.Start:r.CloseTag.End],
-		r.Text.OpenTag.Start, r.Text.OpenTag.End, bytes[r.Text.OpenTag.Start:r.Text.OpenTag.End],
-		r.Text.CloseTag.Start, r.Text.CloseTag.End, bytes[r.Text.CloseTag.Start:r.Text.CloseTag.End],
+		r.OpenTag.Start, r.OpenTag.End, text(r.OpenTag),
+		r.CloseTag.Start, r.CloseTag.End, text(r.CloseTag),
+		r.Text.OpenTag.Start, r.Text.OpenTag.End, text(r.Text.OpenTag),
+		r.Text.CloseTag.Start, r.Text.CloseTag.End, text(r.Text.CloseTag),
 	)
 }
 
@@ -81,6 +111,27 @@ func (dr DocumentRuns) WithText() DocumentRuns {
 	return r
 }
 
+// ShiftFrom shifts all positions of the runs which start at or after the given offset by delta.
+// The offset refers to the bytes before they were changed.
+// It is used to keep the runs in sync after the bytes of the document changed, see Run.SetText.
+func (dr DocumentRuns) ShiftFrom(offset, delta int64) {
+	for _, run := range dr {
+		if run.OpenTag.Start < offset {
+			continue
+		}
+		run.OpenTag.Start += delta
+		run.OpenTag.End += delta
+		run.CloseTag.Start += delta
+		run.CloseTag.End += delta
+		if run.HasText {
+			run.Text.OpenTag.Start += delta
+			run.Text.OpenTag.End += delta
+			run.Text.CloseTag.Start += delta
+			run.Text.CloseTag.End += delta
+		}
+	}
+}
+
 // Push will push a new Run onto the DocumentRuns stack
 func (dr *DocumentRuns) Push(run *Run) {
 	*dr = append(*dr, run)
@@ -93,13 +144,15 @@ func (dr *DocumentRuns) Pop() *Run {
 	return ret
 }
 
-// NewRunID returns the next Fragment.ID
+// NewRunID returns the next Run.ID of the global counter.
+// The RunParser does not use the global counter, run IDs are scoped to the parser instead.
 func NewRunID() int {
-	runId += 1
-	return runId
+	return int(atomic.AddInt64(&runId, 1))
 }
 
-// ResetRunIdCounter will reset the runId counter to 0
+// ResetRunIdCounter will reset the global runId counter to 0
+//
+// Deprecated: Run IDs of parsed documents are scoped to their RunParser, resetting the counter is not necessary.
 func ResetRunIdCounter() {
-	runId = 0
+	atomic.StoreInt64(&runId, 0)
 }
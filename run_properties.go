@@ -0,0 +1,97 @@
+package docx
+
+import (
+	"bytes"
+	"sort"
+)
+
+// byteEdit replaces the bytes between start and end with the data.
+type byteEdit struct {
+	start, end int64
+	data       []byte
+}
+
+// applyEdits applies the non-overlapping edits to the data.
+// The edits are applied from the back, thus the offsets of the remaining edits stay valid.
+func applyEdits(data []byte, edits []byteEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, edit := range edits {
+		data = append(data[:edit.start:edit.start], append(edit.data, data[edit.end:]...)...)
+	}
+	return data
+}
+
+// NormalizeRunProperties rewrites the properties (<w:rPr>) of all runs which contain nothing but fragments of
+// a placeholder to the properties of the run of its first fragment. Word often splits placeholders into runs which
+// only differ by their properties, e.g. if a part of it was formatted differently at some point.
+// Afterwards the placeholder is formatted uniformly, whether it is replaced or kept.
+// Runs which contain any other text keep their properties. The value of a replaced placeholder always has the
+// properties of its first fragment.
+//
+// It should be called before replacing, all modified files are parsed again afterwards.
+func (d *Document) NormalizeRunProperties() error {
+	for name, data := range d.files {
+		edits := runPropertiesEdits(data, d.filePlaceholders[name])
+		if len(edits) == 0 {
+			continue
+		}
+
+		d.files[name] = applyEdits(data, edits)
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPropertiesEdits returns the edits which give all runs covered by a placeholder the properties of its first run.
+func runPropertiesEdits(data []byte, placeholders []*Placeholder) (edits []byteEdit) {
+	edited := make(map[*Run]bool)
+	for _, placeholder := range placeholders {
+		if len(placeholder.Fragments) < 2 {
+			continue
+		}
+		first := placeholder.Fragments[0].Run
+		start, end, ok := runPropertiesPosition(data, first)
+		if !ok {
+			continue
+		}
+		properties := data[start:end]
+
+		// the length of all fragments inside a run tells whether the placeholder covers the whole text
+		covered := make(map[*Run]int64)
+		for _, fragment := range placeholder.Fragments[1:] {
+			covered[fragment.Run] += fragment.Position.End - fragment.Position.Start
+		}
+
+		for _, fragment := range placeholder.Fragments[1:] {
+			run := fragment.Run
+			if run == first || edited[run] || covered[run] != run.Text.CloseTag.Start-run.Text.OpenTag.End {
+				continue
+			}
+			runStart, runEnd, ok := runPropertiesPosition(data, run)
+			if !ok || bytes.Equal(data[runStart:runEnd], properties) {
+				continue
+			}
+			edited[run] = true
+			edits = append(edits, byteEdit{start: runStart, end: runEnd, data: copyBytes(properties)})
+		}
+	}
+	return edits
+}
+
+// runPropertiesPosition returns the position of the properties of the run.
+// If the run has no properties, the empty position right behind the open tag of the run is returned.
+func runPropertiesPosition(data []byte, run *Run) (int64, int64, bool) {
+	head, ok := safeSlice(data, run.OpenTag.End, run.Text.OpenTag.Start)
+	if !run.HasText || !ok {
+		return 0, 0, false
+	}
+	loc := runPropertiesElement.FindIndex(head)
+	if loc == nil {
+		return run.OpenTag.End, run.OpenTag.End, true
+	}
+	// leading whitespace is kept
+	leading := int64(len(head[:loc[1]]) - len(bytes.TrimLeft(head[:loc[1]], " \t\r\n")))
+	return run.OpenTag.End + leading, run.OpenTag.End + int64(loc[1]), true
+}
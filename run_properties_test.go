@@ -0,0 +1,42 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_NormalizeRunProperties(t *testing.T) {
+	template := readFile(t, "./test/run_properties.xml")
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.NormalizeRunProperties(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the runs which only hold fragments of {name} get the properties of its first run
+	data := string(doc.GetFile(DocumentXml))
+	bold := "<w:rPr>\n                    <w:b/>\n                </w:rPr>"
+	if count := strings.Count(data, bold); count != 3 {
+		t.Errorf("not all runs of {name} are bold, have=%d bold runs in %s", count, data)
+	}
+	if strings.Contains(data, "<w:i/>") {
+		t.Error("expected the italic properties to be replaced")
+	}
+	// the last run of {date} holds more text and keeps its properties
+	if !strings.Contains(data, `<w:color w:val="FF0000"/>`) {
+		t.Error("expected the properties of the run with more text to be kept")
+	}
+
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane", "date": "today"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Dear Jane, see today below"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text, want=%s, have=%s", expected, text)
+	}
+	if err := doc.ValidateOutput(); err != nil {
+		t.Error(err)
+	}
+}
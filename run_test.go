@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRun_SetText(t *testing.T) {
+	doc := []byte(`<w:p><w:r><w:t>first</w:t></w:r><w:r><w:br/></w:r><w:r><w:t>second</w:t></w:r></w:p>`)
+	parser := NewRunParser(doc)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	runs := parser.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("unexpected number of runs, want=%d, have=%d", 3, len(runs))
+	}
+
+	offset := runs[0].CloseTag.End
+	doc, delta, err := runs[0].SetText(doc, "a <longer> text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta != 16 {
+		t.Errorf("unexpected delta, want=16, have=%d", delta)
+	}
+	runs.ShiftFrom(offset, delta)
+
+	doc, _, err = runs[2].SetText(doc, "2nd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<w:p><w:r><w:t>a &lt;longer&gt; text</w:t></w:r><w:r><w:br/></w:r><w:r><w:t>2nd</w:t></w:r></w:p>`
+	if string(doc) != expected {
+		t.Errorf("unexpected document, want=%s, have=%s", expected, doc)
+	}
+	if err := ValidatePositions(doc, runs); err != nil {
+		t.Error(err)
+	}
+	if text := runs[2].GetText(doc); text != "2nd" {
+		t.Errorf("unexpected text, want=2nd, have=%s", text)
+	}
+
+	if _, _, err := runs[1].SetText(doc, "text"); !errors.Is(err, ErrRunWithoutText) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrRunWithoutText, err)
+	}
+}
@@ -0,0 +1,113 @@
+package docx
+
+import "fmt"
+
+// Scope selects the files of the document a PlaceholderMap is applied to, see ReplaceScoped.
+type Scope int
+
+const (
+	// ScopeBody is the document itself (word/document.xml).
+	ScopeBody Scope = iota
+	// ScopeHeaders are all headers of the document.
+	ScopeHeaders
+	// ScopeFooters are all footers of the document.
+	ScopeFooters
+	// ScopeAll are all parsed files, just like ReplaceAll.
+	ScopeAll
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case ScopeBody:
+		return "body"
+	case ScopeHeaders:
+		return "headers"
+	case ScopeFooters:
+		return "footers"
+	case ScopeAll:
+		return "all"
+	}
+	return fmt.Sprintf("Scope(%d)", int(s))
+}
+
+// ReplaceScoped applies every PlaceholderMap to the files of its scope in a single call, e.g. to replace a key
+// in the headers with a different value than in the body. It returns how many placeholders were replaced per scope.
+//
+// The specific scopes are applied first, in the order body, headers and footers, and ScopeAll is applied last.
+// Thus the map of ScopeAll only replaces the placeholders which were not replaced by a more specific scope.
+// The value set by SetMissingPlaceholderValue is applied once all maps were replaced.
+func (d *Document) ReplaceScoped(scopedMaps map[Scope]PlaceholderMap) (map[Scope]int, error) {
+	for scope := range scopedMaps {
+		if scope < ScopeBody || scope > ScopeAll {
+			return nil, fmt.Errorf("unable to replace: unknown %s", scope)
+		}
+	}
+
+	counts := make(map[Scope]int, len(scopedMaps))
+	replaced := make(map[string]bool)
+	for scope := ScopeBody; scope <= ScopeAll; scope++ {
+		placeholderMap, exists := scopedMaps[scope]
+		if !exists {
+			continue
+		}
+		counts[scope] = 0
+		for _, name := range d.scopeFiles(scope) {
+			replacer := d.fileReplacers[name]
+			replaceCountBefore := replacer.ReplaceCount
+			changedBytes, err := d.replace(placeholderMap, name)
+			if err != nil {
+				return counts, fmt.Errorf("unable to replace %s in %s: %w", scope, name, err)
+			}
+			if err := d.SetFile(name, changedBytes); err != nil {
+				return counts, err
+			}
+			counts[scope] += replacer.ReplaceCount - replaceCountBefore
+			replaced[name] = true
+		}
+	}
+
+	if d.missingPlaceholderValue != nil {
+		for _, name := range d.scopeFiles(ScopeAll) {
+			if !replaced[name] {
+				continue
+			}
+			replacer := d.fileReplacers[name]
+			if _, err := replacer.ReplaceRemaining(d.markNewlines(d.escapeValue(*d.missingPlaceholderValue))); err != nil {
+				return counts, err
+			}
+			changedBytes, err := d.convertNewlines(name)
+			if err != nil {
+				return counts, err
+			}
+			if err := d.SetFile(name, changedBytes); err != nil {
+				return counts, err
+			}
+		}
+	}
+	return counts, nil
+}
+
+// scopeFiles returns the names of the parsed files which belong to the scope.
+func (d *Document) scopeFiles(scope Scope) []string {
+	var names []string
+	switch scope {
+	case ScopeBody:
+		names = []string{DocumentXml}
+	case ScopeHeaders:
+		names = d.headerFiles
+	case ScopeFooters:
+		names = d.footerFiles
+	case ScopeAll:
+		names = d.allParsedFiles()
+	}
+
+	// only parsed files have a replacer
+	var parsed []string
+	for _, name := range names {
+		if _, exists := d.fileReplacers[name]; exists {
+			parsed = append(parsed, name)
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,49 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_ReplaceScoped(t *testing.T) {
+	header := `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:p><w:r><w:t>{title} {page}</w:t></w:r></w:p></w:hdr>`
+	footer := `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:p><w:r><w:t>{title} {page}</w:t></w:r></w:p></w:ftr>`
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{
+		DocumentXml:        newTestDocumentXml(`<w:p><w:r><w:t>{title} {page}</w:t></w:r></w:p>`),
+		"word/header1.xml": header,
+		"word/footer1.xml": footer,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := doc.ReplaceScoped(map[Scope]PlaceholderMap{
+		ScopeBody:    {"title": "Annual Report"},
+		ScopeHeaders: {"title": "Report"},
+		ScopeAll:     {"title": "unused", "page": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCounts := map[Scope]int{ScopeBody: 1, ScopeHeaders: 1, ScopeAll: 4}
+	if !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("unexpected counts, want=%v, have=%v", expectedCounts, counts)
+	}
+	expected := map[string]string{
+		DocumentXml:        "Annual Report 1",
+		"word/header1.xml": "Report 1",
+		"word/footer1.xml": "unused 1",
+	}
+	for file, text := range expected {
+		if have := writtenText(t, doc, file); have != text {
+			t.Errorf("unexpected text of %s, want=%s, have=%s", file, text, have)
+		}
+	}
+
+	if _, err := doc.ReplaceScoped(map[Scope]PlaceholderMap{Scope(42): {"title": "x"}}); err == nil {
+		t.Error("expected an error for an unknown scope")
+	}
+}
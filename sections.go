@@ -0,0 +1,31 @@
+package docx
+
+import (
+	"regexp"
+)
+
+var (
+	// SectionPropertiesTagRegex matches the open tag of the section properties (<w:sectPr>) which end every section.
+	SectionPropertiesTagRegex = regexp.MustCompile(`<w:sectPr[\s>/]`)
+	// ParagraphTagRegex matches the open tag of a paragraph (<w:p>).
+	ParagraphTagRegex = regexp.MustCompile(`<w:p[\s>/]`)
+	// sectionPropertiesChangeRegex matches recorded previous section properties of tracked changes.
+	sectionPropertiesChangeRegex = regexp.MustCompile(`(?s)<w:sectPrChange\b[^>]*>.*?</w:sectPrChange>`)
+)
+
+// SectionCount returns the number of sections of the document, which is the number of section properties (<w:sectPr>)
+// inside the DocumentXml. Section properties recorded by tracked changes are not counted.
+// Every document has at least one section, even if it does not declare its properties.
+func (d *Document) SectionCount() int {
+	data := sectionPropertiesChangeRegex.ReplaceAll(d.GetFile(DocumentXml), nil)
+	if count := len(SectionPropertiesTagRegex.FindAllIndex(data, -1)); count > 0 {
+		return count
+	}
+	return 1
+}
+
+// ParagraphCount returns the number of paragraphs inside the DocumentXml. It is a rough metric, all paragraphs
+// are counted including empty ones and those inside of tables and text boxes.
+func (d *Document) ParagraphCount() int {
+	return len(ParagraphTagRegex.FindAllIndex(d.GetFile(DocumentXml), -1))
+}
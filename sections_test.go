@@ -0,0 +1,129 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+var sectPrRegex = regexp.MustCompile(`(?s)<w:sectPr>.*?</w:sectPr>`)
+
+func TestReplace_MultipleSections(t *testing.T) {
+	template := readFile(t, "./test/sections.xml")
+	sections := sectPrRegex.FindAll(template, -1)
+	if len(sections) != 4 {
+		t.Fatalf("unexpected number of sections in the fixture, want=%d, have=%d", 4, len(sections))
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "empty values", value: "", expected: "Cover: Landscape  and "},
+		{name: "short values", value: "x", expected: "Cover: xxLandscape x and xx"},
+		{name: "long values", value: "a much longer value than the placeholder",
+			expected: "Cover: a much longer value than the placeholder" +
+				"a much longer value than the placeholder" +
+				"Landscape a much longer value than the placeholder and a much longer value than the placeholder" +
+				"a much longer value than the placeholder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.ReplaceAll(PlaceholderMap{
+				"title": tt.value, "subtitle": tt.value, "table": tt.value, "notes": tt.value, "end": tt.value,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if text := writtenText(t, doc, DocumentXml); text != tt.expected {
+				t.Errorf("unexpected text, want=%q, have=%q", tt.expected, text)
+			}
+			if written := sectPrRegex.FindAll(writtenFile(t, doc, DocumentXml), -1); !reflect.DeepEqual(written, sections) {
+				t.Errorf("section properties were not kept, want=%s, have=%s",
+					bytes.Join(sections, nil), bytes.Join(written, nil))
+			}
+			if err := doc.ValidateOutput(); err != nil {
+				t.Errorf("output is invalid, have=%s", err)
+			}
+		})
+	}
+}
+
+func TestReplacer_UnsafeCut(t *testing.T) {
+	template := readFile(t, "./test/sections.xml")
+	parser := NewRunParser(template)
+	if err := parser.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subtitle *Placeholder
+	for _, placeholder := range placeholders {
+		if placeholder.Text(template) == "{subtitle}" {
+			subtitle = placeholder
+		}
+	}
+	if subtitle == nil {
+		t.Fatal("placeholder {subtitle} not found")
+	}
+
+	// corrupt the last fragment of {subtitle} so that it reaches back into the preceding <w:sectPr>
+	last := subtitle.Fragments[len(subtitle.Fragments)-1]
+	last.Position.Start -= 100
+
+	original := append([]byte(nil), template...)
+	replacer := NewReplacer(template, placeholders)
+	err = replacer.replacePlaceholder(subtitle, "value")
+	if !errors.Is(err, ErrUnsafeCut) {
+		t.Fatalf("unexpected error, want=%v, have=%v", ErrUnsafeCut, err)
+	}
+	if !bytes.Equal(replacer.Bytes(), original) {
+		t.Error("expected the document to be untouched")
+	}
+}
+
+func TestDocument_SectionCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		document   string
+		sections   int
+		paragraphs int
+	}{
+		{name: "fixture", document: string(readFile(t, "./test/sections.xml")), sections: 4, paragraphs: 5},
+		{
+			// tracked section properties are ignored
+			name: "tracked section properties",
+			document: newTestDocumentXml(`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Title</w:t></w:r></w:p><w:p/>` +
+				`<w:sectPr><w:sectPrChange w:id="1"><w:sectPr/></w:sectPrChange></w:sectPr>`),
+			sections:   1,
+			paragraphs: 2,
+		},
+		{name: "without section properties", document: newTestDocumentXml(""), sections: 1, paragraphs: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: tt.document}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count := doc.SectionCount(); count != tt.sections {
+				t.Errorf("unexpected section count, want=%d, have=%d", tt.sections, count)
+			}
+			if count := doc.ParagraphCount(); count != tt.paragraphs {
+				t.Errorf("unexpected paragraph count, want=%d, have=%d", tt.paragraphs, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package docx
+
+import (
+	"encoding/xml"
+)
+
+const (
+	// SettingsXml holds the document settings, e.g. the default tab stop.
+	SettingsXml = "word/settings.xml"
+
+	// DefaultTabStopTwips is the default tab stop Word uses if the document does not define one (half an inch).
+	DefaultTabStopTwips = 720
+)
+
+// settingsXml is the root element of the SettingsXml, only the settings used by the library are mapped.
+type settingsXml struct {
+	DefaultTabStop *struct {
+		Val int `xml:"val,attr"`
+	} `xml:"defaultTabStop"`
+}
+
+// DefaultTabStop returns the distance between the default tab stops of the document in twips (1/1440 inch).
+// Tabs (<w:tab/>) without a custom tab stop in the paragraph properties advance to the next multiple of it.
+// If the settings do not define a valid default tab stop, DefaultTabStopTwips is returned.
+func (d *Document) DefaultTabStop() int {
+	data, err := d.readRawFile(SettingsXml)
+	if err != nil {
+		return DefaultTabStopTwips
+	}
+	var settings settingsXml
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		logger.Printf("unable to parse %s: %s", SettingsXml, err)
+		return DefaultTabStopTwips
+	}
+	if settings.DefaultTabStop == nil || settings.DefaultTabStop.Val <= 0 {
+		return DefaultTabStopTwips
+	}
+	return settings.DefaultTabStop.Val
+}
@@ -0,0 +1,35 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestDocument_DefaultTabStop(t *testing.T) {
+	settings := func(content string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + content + `</w:settings>`
+	}
+
+	tests := []struct {
+		name     string
+		docx     []byte
+		expected int
+	}{
+		{name: "template", docx: newTestDocx(t, nil), expected: 720},
+		{name: "custom", docx: newTestDocx(t, map[string]string{SettingsXml: settings(`<w:zoom w:percent="100"/><w:defaultTabStop w:val="708"/>`)}), expected: 708},
+		{name: "undefined", docx: newTestDocx(t, map[string]string{SettingsXml: settings(`<w:zoom w:percent="100"/>`)}), expected: DefaultTabStopTwips},
+		{name: "invalid", docx: newTestDocx(t, map[string]string{SettingsXml: settings(`<w:defaultTabStop w:val="wide"/>`)}), expected: DefaultTabStopTwips},
+		{name: "missing settings", docx: withoutFiles(t, newTestDocx(t, nil), SettingsXml), expected: DefaultTabStopTwips},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OpenBytes(tt.docx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tabStop := doc.DefaultTabStop(); tabStop != tt.expected {
+				t.Errorf("unexpected tab stop, want=%d, have=%d", tt.expected, tabStop)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStrayDelimiter is returned if SetCheckStrayDelimiters is enabled and a file contains a delimiter
+// which does not belong to a placeholder after replacing.
+var ErrStrayDelimiter = errors.New("stray delimiter after replacing")
+
+// SetCheckStrayDelimiters enables or disables checking the text of every replaced file for stray delimiters,
+// i.e. open or close delimiters which do not belong to a complete placeholder.
+// If the parser only found some fragments of a placeholder, e.g. because of an element it does not handle,
+// replacing may leave a part of the placeholder behind while the amount of replaced placeholders still matches.
+// With the check enabled, replacing fails with ErrStrayDelimiter instead of silently producing such a document.
+// Delimiters which are meant literally must be escaped in the template (e.g. '{{'). It is disabled by default.
+func (d *Document) SetCheckStrayDelimiters(check bool) {
+	d.checkStrayDelimiters = check
+}
+
+// checkStray returns an ErrStrayDelimiter if the text of the data contains stray delimiters.
+func (d *Document) checkStray(file string, data []byte) error {
+	text := d.stripXmlTags(string(data))
+	stray := strayDelimiters(text, d.delimiters)
+	if len(stray) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d in %s, the first is at %q", ErrStrayDelimiter, len(stray), file, excerpt(text, stray[0]))
+}
+
+// strayDelimiters returns the positions of all delimiters inside the text which do not belong to a placeholder.
+// If placeholders are nested, the outer delimiters are stray, just like countDelimitedPlaceholders only counts
+// the innermost placeholder.
+func strayDelimiters(text string, delims delimiters) (stray []int) {
+	openPos, closePos := delimiterPositions(text, false, delims)
+
+	lastOpen := -1
+	for len(openPos) > 0 || len(closePos) > 0 {
+		if len(openPos) > 0 && (len(closePos) == 0 || openPos[0] < closePos[0]) {
+			if lastOpen >= 0 {
+				stray = append(stray, lastOpen)
+			}
+			lastOpen = openPos[0]
+			openPos = openPos[1:]
+			continue
+		}
+		if lastOpen < 0 {
+			stray = append(stray, closePos[0])
+		}
+		lastOpen = -1
+		closePos = closePos[1:]
+	}
+	if lastOpen >= 0 {
+		stray = append(stray, lastOpen)
+	}
+	return stray
+}
+
+// excerpt returns up to 20 bytes of the text around the position.
+func excerpt(text string, pos int) string {
+	start, end := pos-10, pos+10
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	return text[start:end]
+}
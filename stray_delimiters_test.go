@@ -0,0 +1,50 @@
+package docx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStrayDelimiters(t *testing.T) {
+	tests := []struct {
+		text  string
+		stray []int
+	}{
+		{text: "{foo} and {bar}"},
+		{text: "{{literal}} and {foo}"},
+		{text: "value} and {foo}", stray: []int{5}},
+		{text: "{foo and {bar}", stray: []int{0}},
+		{text: "{foo} and {bar", stray: []int{10}},
+	}
+	for _, tt := range tests {
+		if stray := strayDelimiters(tt.text, defaultDelimiters); !reflect.DeepEqual(stray, tt.stray) {
+			t.Errorf("unexpected stray delimiters of '%s', want=%v, have=%v", tt.text, tt.stray, stray)
+		}
+	}
+}
+
+func TestDocument_SetCheckStrayDelimiters(t *testing.T) {
+	// the parser only knows one text per run, thus '{na' is never replaced.
+	// The amount of '{name}' placeholders matches by coincidence, since '{na{name}' contains exactly one of them.
+	body := `<w:p><w:r><w:t>{{literal}} {unknown}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{name}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{na</w:t><w:tab/><w:t>{name}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "{Alice}"}); err != nil {
+		t.Fatalf("check is not disabled by default, have=%s", err)
+	}
+
+	doc = openTestDocument(t, body)
+	doc.SetCheckStrayDelimiters(true)
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "{Alice}"}); !errors.Is(err, ErrStrayDelimiter) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrStrayDelimiter, err)
+	}
+
+	// escaped delimiters, including the ones of the values, and unreplaced placeholders are no stray delimiters
+	doc = openTestDocument(t, `<w:p><w:r><w:t>{{literal}} {unknown} {name}</w:t></w:r></w:p>`)
+	doc.SetCheckStrayDelimiters(true)
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "{Alice}"}); err != nil {
+		t.Errorf("unexpected error, have=%s", err)
+	}
+}
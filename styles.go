@@ -0,0 +1,124 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+)
+
+// StylesXml is the part which holds the style definitions of the document.
+const StylesXml = "word/styles.xml"
+
+var (
+	// ErrStyleNotFound is returned if the requested style is not defined in the StylesXml.
+	ErrStyleNotFound = errors.New("style not found in document")
+
+	// runStyleRegex matches the style reference inside the properties of a run.
+	runStyleRegex = regexp.MustCompile(`<w:rStyle\b[^>]*/>`)
+	// runPropertiesOpenTagRegex matches the open tag of the properties of a run.
+	runPropertiesOpenTagRegex = regexp.MustCompile(`<w:rPr\b[^>]*>`)
+)
+
+// stylesXml is the part of the StylesXml which is required to look up the styles.
+type stylesXml struct {
+	// DefaultLanguage is the language of all text which does not define its own language.
+	DefaultLanguage *struct {
+		Val string `xml:"val,attr"`
+	} `xml:"docDefaults>rPrDefault>rPr>lang"`
+	Styles []struct {
+		Type string `xml:"type,attr"`
+		ID   string `xml:"styleId,attr"`
+	} `xml:"style"`
+}
+
+// styles returns the parsed StylesXml.
+func (d *Document) styles() (stylesXml, error) {
+	var styles stylesXml
+	data, err := d.readRawFile(StylesXml)
+	if err != nil {
+		return styles, err
+	}
+	if err := xml.Unmarshal(data, &styles); err != nil {
+		return styles, fmt.Errorf("unable to parse %s: %s", StylesXml, err)
+	}
+	return styles, nil
+}
+
+// Styles returns the ids of all styles defined in the StylesXml, in the order of their definition.
+// These are the ids which are referenced by the document, not the names shown by Word.
+func (d *Document) Styles() ([]string, error) {
+	styles, err := d.styles()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(styles.Styles))
+	for _, style := range styles.Styles {
+		ids = append(ids, style.ID)
+	}
+	return ids, nil
+}
+
+// ReplaceWithStyle replaces all placeholders with the given key by the value, which is formatted with the
+// character style of the given id. The value is placed into its own run which has the properties of the run
+// of the placeholder and references the style (<w:rStyle>). All modified files are parsed again afterwards.
+//
+// If the style is not defined, ErrStyleNotFound is returned. Only character styles can be applied to text.
+// If no placeholder with the given key exists, ErrPlaceholderNotFound is returned.
+func (d *Document) ReplaceWithStyle(key, value, styleID string) error {
+	styles, err := d.styles()
+	if err != nil {
+		return err
+	}
+	styleType := ""
+	for _, style := range styles.Styles {
+		if style.ID == styleID {
+			styleType = style.Type
+		}
+	}
+	if styleType == "" {
+		return fmt.Errorf("%w: %s", ErrStyleNotFound, styleID)
+	}
+	if styleType != "character" {
+		return fmt.Errorf("unable to apply style %s: it is a %s style, not a character style", styleID, styleType)
+	}
+
+	files, err := d.markPlaceholders(key)
+	if err != nil {
+		return err
+	}
+	escaped := html.EscapeString(d.escapeValue(value))
+	for _, file := range files {
+		d.files[file.name] = replaceMarkers(file.data, func(data []byte, pos int) string {
+			return styledRunBreak(data, pos, escaped, styleID)
+		})
+		if err := d.parseFile(file.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// styledRunBreak returns the markup which ends the text and run enclosing the offset, inserts a run containing the
+// text with the given style and starts a new run with the original properties for the remaining text.
+func styledRunBreak(data []byte, offset int, text, styleID string) string {
+	var runProperties []byte
+	if start := lastOpenTag(data[:offset], "w:r"); start >= 0 {
+		runProperties = bytes.TrimSpace(runPropertiesElement.Find(data[start:offset]))
+	}
+
+	styleReference := fmt.Sprintf(`<w:rStyle w:val="%s"/>`, html.EscapeString(styleID))
+	var styledProperties string
+	if loc := runPropertiesOpenTagRegex.FindIndex(runProperties); loc != nil && runProperties[loc[1]-2] != '/' {
+		// the style must be the first of the properties, an existing one is replaced
+		properties := runStyleRegex.ReplaceAll(runProperties[loc[1]:], nil)
+		styledProperties = string(runProperties[:loc[1]]) + styleReference + string(properties)
+	} else {
+		styledProperties = `<w:rPr>` + styleReference + `</w:rPr>`
+	}
+
+	return `</w:t></w:r><w:r>` + styledProperties + `<w:t xml:space="preserve">` + text + `</w:t></w:r>` +
+		`<w:r>` + string(runProperties) + `<w:t xml:space="preserve">`
+}
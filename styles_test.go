@@ -0,0 +1,81 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Styles(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err := doc.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(styles) == 0 || styles[0] != "para0" {
+		t.Fatalf("unexpected first style, want=%s, have=%q", "para0", styles)
+	}
+	for _, expected := range []string{"char0", "TableGrid"} {
+		if !strings.Contains(strings.Join(styles, " "), expected) {
+			t.Errorf("missing style, want=%s, have=%q", expected, styles)
+		}
+	}
+}
+
+func TestDocument_ReplaceWithStyle(t *testing.T) {
+	body := `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Hello {name}!</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{name}</w:t></w:r><w:r><w:t>{other}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	if err := doc.ReplaceWithStyle("name", "value", "missing"); !errors.Is(err, ErrStyleNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrStyleNotFound, err)
+	}
+	if err := doc.ReplaceWithStyle("name", "value", "para1"); err == nil {
+		t.Error("expected an error for a paragraph style")
+	}
+	if err := doc.ReplaceWithStyle("missing", "value", "char0"); !errors.Is(err, ErrPlaceholderNotFound) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrPlaceholderNotFound, err)
+	}
+	if err := doc.ReplaceWithStyle("name", "A & {B}", "char0"); err != nil {
+		t.Fatal(err)
+	}
+	// the document is parsed again, thus the remaining placeholders can still be replaced
+	if err := doc.ReplaceAll(PlaceholderMap{"other": "done"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if text := writtenText(t, doc, DocumentXml); text != "Hello A & {B}!A & {B}done" {
+		t.Errorf("unexpected text, want=%q, have=%q", "Hello A & {B}!A & {B}done", text)
+	}
+	document := string(writtenFile(t, doc, DocumentXml))
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:rStyle w:val="char0"/><w:b/></w:rPr><w:t xml:space="preserve">A &amp; {B}</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">!</w:t>`,
+		`<w:r><w:rPr><w:rStyle w:val="char0"/></w:rPr><w:t xml:space="preserve">A &amp; {B}</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected document to contain %s", expected)
+		}
+	}
+}
+
+func TestDocument_ReplaceWithStyle_Failure(t *testing.T) {
+	doc := openTestDocument(t, `<w:p><w:r><w:t>{key}</w:t></w:r></w:p>`)
+	// the placeholder of the footer cannot be replaced, the one of the document is marked before
+	footer := doc.footerFiles[0]
+	run := doc.filePlaceholders[footer][0].Fragments[0].Run
+	run.Text.CloseTag.Start = run.Text.OpenTag.End
+
+	if err := doc.ReplaceWithStyle("key", "value", "char0"); !errors.Is(err, ErrUnsafeCut) {
+		t.Fatalf("unexpected error, want=%v, have=%v", ErrUnsafeCut, err)
+	}
+	if document := writtenFile(t, doc, DocumentXml); bytes.Contains(document, []byte(placeholderMarker)) {
+		t.Errorf("marker left behind after the failure, have=%s", document)
+	}
+	if text := writtenText(t, doc, DocumentXml); text != "{key}" {
+		t.Errorf("unexpected text, want=%s, have=%s", "{key}", text)
+	}
+}
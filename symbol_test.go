@@ -0,0 +1,41 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestReplace_SymbolRuns(t *testing.T) {
+	template := readFile(t, "./test/symbols.xml")
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(template)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// symbols do not have a text, they must neither hide nor break adjacent placeholders
+	var texts []string
+	for _, placeholder := range doc.filePlaceholders[DocumentXml] {
+		texts = append(texts, placeholder.Text(doc.files[DocumentXml]))
+	}
+	expected := []string{"{task}", "{angle}", "{from}", "{to}"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("unexpected placeholders, want=%v, have=%v", expected, texts)
+	}
+
+	err = doc.ReplaceAll(PlaceholderMap{"task": "Review", "angle": "45", "from": "Berlin", "to": "Paris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedText := " Review doneAngle: 45Berlin  Paris"
+	if text := writtenText(t, doc, DocumentXml); text != expectedText {
+		t.Errorf("unexpected text, want=%s, have=%s", expectedText, text)
+	}
+	symbolRegex := regexp.MustCompile(`<w:sym [^>]*/>`)
+	symbols := symbolRegex.FindAll(template, -1)
+	if written := symbolRegex.FindAll(writtenFile(t, doc, DocumentXml), -1); !reflect.DeepEqual(written, symbols) {
+		t.Errorf("symbols were not kept, want=%s, have=%s", bytes.Join(symbols, nil), bytes.Join(written, nil))
+	}
+}
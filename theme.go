@@ -0,0 +1,67 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ThemeXml is the usual location of the theme of the document.
+	ThemeXml = "word/theme/theme1.xml"
+	// ThemeRelationshipType is the type of the relationship from the document to its theme.
+	ThemeRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+)
+
+// themeXml is the root element of the theme, only the color scheme is mapped.
+type themeXml struct {
+	ColorScheme struct {
+		Colors []struct {
+			XMLName xml.Name
+			RGB     *struct {
+				Val string `xml:"val,attr"`
+			} `xml:"srgbClr"`
+			System *struct {
+				LastColor string `xml:"lastClr,attr"`
+			} `xml:"sysClr"`
+		} `xml:",any"`
+	} `xml:"themeElements>clrScheme"`
+}
+
+// ThemeColors returns the colors of the color scheme of the document theme by their name, e.g. 'accent1' or 'dk1'.
+// The colors are hex RGB values like '4F81BD'. System colors (e.g. the window text color) are resolved to
+// the color they had when the document was saved.
+// The theme is looked up through the relationships of the document, if there is none ThemeXml is used.
+func (d *Document) ThemeColors() (map[string]string, error) {
+	part := ThemeXml
+	rels, err := d.Relationships(DocumentXml)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		if rel.Type == ThemeRelationshipType {
+			part = relationshipTargetPath(DocumentXml, rel.Target)
+			break
+		}
+	}
+
+	data, err := d.readRawFile(part)
+	if err != nil {
+		return nil, err
+	}
+	var theme themeXml
+	if err := xml.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", part, err)
+	}
+
+	colors := make(map[string]string, len(theme.ColorScheme.Colors))
+	for _, color := range theme.ColorScheme.Colors {
+		switch {
+		case color.RGB != nil:
+			colors[color.XMLName.Local] = strings.ToUpper(color.RGB.Val)
+		case color.System != nil && color.System.LastColor != "":
+			colors[color.XMLName.Local] = strings.ToUpper(color.System.LastColor)
+		}
+	}
+	return colors, nil
+}
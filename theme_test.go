@@ -0,0 +1,37 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_ThemeColors(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors, err := doc.ThemeColors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"dk1": "000000", "lt1": "FFFFFF", "dk2": "1F497D", "lt2": "EEECE1",
+		"accent1": "4F81BD", "accent2": "C0504D", "accent3": "9BBB59",
+		"accent4": "8064A2", "accent5": "4BACC6", "accent6": "F79646",
+		"hlink": "0000FF", "folHlink": "800080",
+	}
+	if !reflect.DeepEqual(colors, expected) {
+		t.Errorf("unexpected theme colors, want=%v, have=%v", expected, colors)
+	}
+
+	// documents without a theme return an error
+	doc, err = OpenBytes(newTestDocx(t, map[string]string{"word/_rels/document.xml.rels": `<Relationships ` +
+		`xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" ` +
+		`Type="` + ThemeRelationshipType + `" Target="theme/missing.xml"/></Relationships>`}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.ThemeColors(); err == nil {
+		t.Error("expected an error if the theme is missing")
+	}
+}
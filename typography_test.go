@@ -0,0 +1,33 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestReplace_TypographicCharacters(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, map[string]string{DocumentXml: string(readFile(t, "./test/typography.xml"))}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the values contain multi-byte characters as well and have a different byte length than their placeholders
+	err = doc.ReplaceAll(PlaceholderMap{
+		"quote":  "Größe ist relativ",
+		"author": "Zoë",
+		"friend": "—",
+		"de":     "Grüße",
+		"fr":     "Ça va",
+		"a":      "1",
+		"b":      "２",
+		"end":    "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "“Größe ist relativ” – said Zoë’s friend — ‘—’…" +
+		"„Grüße“ – «Ça va» – ‹1›–‹２›——"
+	if text := writtenText(t, doc, DocumentXml); text != expected {
+		t.Errorf("unexpected text\nwant=%s\nhave=%s", expected, text)
+	}
+}
@@ -0,0 +1,109 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrMissingPart is returned if the archive lacks a part which every docx document requires.
+var ErrMissingPart = errors.New("mandatory part missing in docx archive")
+
+// ValidateArchive checks that the archive contains all parts a valid docx package requires: the ContentTypesXml,
+// the PackageRelationshipsXml and the DocumentXml. Word refuses to open archives without them, even if the
+// document itself is fine. It is called when opening a document, but can be called independently as well.
+//
+// The errors of all missing parts are returned combined (see errors.Join), each wrapping ErrMissingPart.
+func (d *Document) ValidateArchive() error {
+	parts := make(map[string]bool)
+	for _, name := range d.partNames() {
+		parts[name] = true
+	}
+
+	var errs []error
+	for _, name := range []string{ContentTypesXml, PackageRelationshipsXml, DocumentXml} {
+		if !parts[name] {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingPart, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateOutput checks that all files which are written from memory, i.e. the parsed files and all files
+// modified or added through the Document API, are well-formed XML as they would be written by Write().
+// Additionally, the tag positions of all replaced runs are validated (see ValidatePositions).
+// This allows to verify the document before it is handed to Word, which refuses to open corrupt files.
+//
+// The errors of all invalid files are returned combined (see errors.Join), each prefixed with the name of the file.
+func (d *Document) ValidateOutput() error {
+	var names []string
+	for name := range d.files {
+		names = append(names, name)
+	}
+	for name := range d.rawFiles {
+		if _, parsed := d.files[name]; !parsed && isXmlPart(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		data, isRaw := d.rawFiles[name]
+		if !isRaw {
+			data = d.outputFile(name)
+		}
+		if err := checkWellFormed(data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		// the positions are only known as long as the file was not replaced by SetFile
+		replacer, exists := d.fileReplacers[name]
+		if isRaw || !exists || !bytes.Equal(replacer.Bytes(), d.files[name]) {
+			continue
+		}
+		if err := ValidatePositions(d.files[name], replacer.distinctRuns); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isXmlPart returns true if the part of the archive contains XML.
+func isXmlPart(name string) bool {
+	return strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".rels")
+}
+
+// checkWellFormed reads all tokens of the data and returns an error if the data is not well-formed XML.
+func checkWellFormed(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	roots := 0
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("malformed xml: %w", err)
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if roots != 1 {
+		return fmt.Errorf("malformed xml: expected one root element, found %d", roots)
+	}
+	return nil
+}
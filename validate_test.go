@@ -0,0 +1,64 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ValidateOutput(t *testing.T) {
+	doc, err := Open("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.ReplaceAll(PlaceholderMap{"key": "<value> & \"quotes\""}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ValidateOutput(); err != nil {
+		t.Errorf("replaced document is invalid, have=%s", err)
+	}
+
+	// values are escaped by the replacer, broken XML can only be introduced through SetFile
+	broken := strings.Replace(string(doc.GetFile(DocumentXml)), "</w:body>", "", 1)
+	if err := doc.SetFile(DocumentXml, []byte(broken)); err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ValidateOutput()
+	if err == nil || !strings.HasPrefix(err.Error(), DocumentXml) {
+		t.Errorf("%s is not invalid, have=%v", DocumentXml, err)
+	}
+}
+
+func TestDocument_ValidateOutput_Positions(t *testing.T) {
+	body := `<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`
+	doc := openTestDocument(t, body)
+
+	// corrupt the offsets without breaking the XML
+	doc.fileReplacers[DocumentXml].distinctRuns[0].Text.OpenTag.Start++
+	if err := doc.ValidateOutput(); !errors.Is(err, ErrTagsInvalid) {
+		t.Errorf("unexpected error, want=%v, have=%v", ErrTagsInvalid, err)
+	}
+}
+
+func TestDocument_ValidateArchive(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ValidateArchive(); err != nil {
+		t.Errorf("template is invalid, have=%s", err)
+	}
+
+	// archives without the mandatory parts are rejected when opening them
+	_, err = OpenBytes(withoutFiles(t, newTestDocx(t, nil), ContentTypesXml, PackageRelationshipsXml))
+	if !errors.Is(err, ErrMissingPart) {
+		t.Fatalf("unexpected error, want=%v, have=%v", ErrMissingPart, err)
+	}
+	for _, name := range []string{ContentTypesXml, PackageRelationshipsXml} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not name the part, want=%s, have=%s", name, err)
+		}
+	}
+}
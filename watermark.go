@@ -0,0 +1,182 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// HeaderContentType is the content type of header parts.
+	HeaderContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	// HeaderRelationshipType is the type of the relationship from the document to its header parts.
+	HeaderRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+
+	// watermarkShapeID is the id prefix Word uses for watermark shapes, it is used to find existing watermarks.
+	watermarkShapeID = "PowerPlusWaterMarkObject"
+
+	vmlNamespace    = "urn:schemas-microsoft-com:vml"
+	officeNamespace = "urn:schemas-microsoft-com:office:office"
+)
+
+var (
+	// paragraphOpenTagRegex matches the open tag of a paragraph including its properties, if there are any.
+	paragraphOpenTagRegex = regexp.MustCompile(`(?s)<w:p(?:\s[^>]*[^/])?>\s*(?:<w:pPr>.*?</w:pPr>|<w:pPr/>)?`)
+	// sectionPropertiesOpenTagRegex matches the open tag of the section properties, which may be a singleton tag.
+	sectionPropertiesOpenTagRegex = regexp.MustCompile(`<w:sectPr(\s[^>]*?)?(/?)>`)
+)
+
+// WatermarkOptions configure the appearance of a watermark.
+// The zero value results in a semitransparent, silver watermark using the 'Calibri' font.
+type WatermarkOptions struct {
+	// Color of the text, either a name (e.g. 'silver') or a hex value (e.g. '#C0C0C0').
+	Color string
+	// Opacity of the text between 0 (invisible) and 1 (opaque). 0 results in the default of 0.5.
+	Opacity float64
+	// Font used to render the text.
+	Font string
+}
+
+// withDefaults returns the options with all unset values replaced by their defaults.
+func (o WatermarkOptions) withDefaults() WatermarkOptions {
+	if o.Color == "" {
+		o.Color = "silver"
+	}
+	if o.Opacity <= 0 || o.Opacity > 1 {
+		o.Opacity = 0.5
+	}
+	if o.Font == "" {
+		o.Font = "Calibri"
+	}
+	return o
+}
+
+// SetWatermark inserts a diagonal text watermark (e.g. 'DRAFT' or 'CONFIDENTIAL') into all headers of the document.
+// An existing watermark is replaced. If the document does not have any headers, a header containing
+// the watermark is added to all sections of the document.
+func (d *Document) SetWatermark(text string, opts WatermarkOptions) error {
+	if len(d.headerFiles) == 0 {
+		if err := d.addDefaultHeader(); err != nil {
+			return fmt.Errorf("unable to add header for watermark: %w", err)
+		}
+	}
+
+	run := watermarkRun(text, opts.withDefaults())
+	for _, name := range d.headerFiles {
+		data := removeWatermark(d.files[name])
+		data = declareNamespace(data, "w:hdr", "v", vmlNamespace)
+		data = declareNamespace(data, "w:hdr", "o", officeNamespace)
+
+		// the watermark is anchored in the first paragraph of the header
+		if loc := paragraphOpenTagRegex.FindIndex(data); loc != nil {
+			data = append(data[:loc[1]], append([]byte(run), data[loc[1]:]...)...)
+		} else {
+			data = insertBeforeClosingTag(data, "</w:hdr>", "<w:p>"+run+"</w:p>")
+		}
+
+		d.files[name] = data
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watermarkRun returns a run which holds the VML shape of the watermark.
+func watermarkRun(text string, opts WatermarkOptions) string {
+	return `<w:r><w:rPr><w:noProof/></w:rPr><w:pict>` +
+		`<v:shapetype id="_x0000_t136" coordsize="21600,21600" o:spt="136" adj="10800" path="m@7,l@8,m@5,21600l@6,21600e">` +
+		`<v:formulas><v:f eqn="sum #0 0 10800"/><v:f eqn="prod #0 2 1"/><v:f eqn="sum 21600 0 @1"/><v:f eqn="sum 0 0 @2"/>` +
+		`<v:f eqn="sum 21600 0 @3"/><v:f eqn="if @0 @3 0"/><v:f eqn="if @0 21600 @1"/><v:f eqn="if @0 0 @2"/>` +
+		`<v:f eqn="if @0 @4 21600"/><v:f eqn="mid @5 @6"/><v:f eqn="mid @8 @5"/><v:f eqn="mid @7 @8"/>` +
+		`<v:f eqn="mid @6 @7"/><v:f eqn="sum @6 0 @5"/></v:formulas>` +
+		`<v:path textpathok="t" o:connecttype="custom" o:connectlocs="@9,0;@10,10800;@11,21600;@12,10800" o:connectangles="270,180,90,0"/>` +
+		`<v:textpath on="t" fitshape="t"/><v:handles><v:h position="#0,bottomRight" xrange="6629,14971"/></v:handles>` +
+		`<o:lock v:ext="edit" text="t" shapetype="t"/></v:shapetype>` +
+		`<v:shape id="` + watermarkShapeID + `1" o:spid="_x0000_s2049" type="#_x0000_t136" ` +
+		`style="position:absolute;margin-left:0;margin-top:0;width:468pt;height:117pt;rotation:315;z-index:-251657216;` +
+		`mso-position-horizontal:center;mso-position-horizontal-relative:margin;` +
+		`mso-position-vertical:center;mso-position-vertical-relative:margin" ` +
+		`o:allowincell="f" fillcolor="` + html.EscapeString(opts.Color) + `" stroked="f">` +
+		`<v:fill opacity="` + strconv.FormatFloat(opts.Opacity, 'f', -1, 64) + `"/>` +
+		`<v:textpath style="font-family:&quot;` + html.EscapeString(opts.Font) + `&quot;;font-size:1pt" string="` + html.EscapeString(text) + `"/>` +
+		`</v:shape></w:pict></w:r>`
+}
+
+// removeWatermark removes the run holding an existing watermark from the data.
+func removeWatermark(data []byte) []byte {
+	for {
+		pos := bytes.Index(data, []byte(`id="`+watermarkShapeID))
+		if pos < 0 {
+			return data
+		}
+		start := bytes.LastIndex(data[:pos], []byte("<w:r>"))
+		if other := bytes.LastIndex(data[:pos], []byte("<w:r ")); other > start {
+			start = other
+		}
+		end := bytes.Index(data[pos:], []byte("</w:r>"))
+		if start < 0 || end < 0 {
+			return data
+		}
+		end += pos + len("</w:r>")
+		data = append(data[:start], data[end:]...)
+	}
+}
+
+// declareNamespace adds the namespace declaration to the first element with the given name, if it is missing.
+func declareNamespace(data []byte, element, prefix, uri string) []byte {
+	openTagRegex := regexp.MustCompile(`<` + regexp.QuoteMeta(element) + `(\s[^>]*)?>`)
+	loc := openTagRegex.FindIndex(data)
+	if loc == nil || bytes.Contains(data[loc[0]:loc[1]], []byte("xmlns:"+prefix+"=")) {
+		return data
+	}
+	declaration := fmt.Sprintf(` xmlns:%s="%s"`, prefix, uri)
+	insertPos := loc[0] + 1 + len(element)
+	var modified []byte
+	modified = append(modified, data[:insertPos]...)
+	modified = append(modified, []byte(declaration)...)
+	modified = append(modified, data[insertPos:]...)
+	return modified
+}
+
+// addDefaultHeader adds an empty header to the document which is referenced as default header by all sections.
+func (d *Document) addDefaultHeader() error {
+	name := ""
+	for i := 1; name == ""; i++ {
+		candidate := fmt.Sprintf("word/header%d.xml", i)
+		if _, err := d.readRawFile(candidate); err != nil {
+			name = candidate
+		}
+	}
+
+	header := XmlDeclaration + "\n" +
+		`<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:p></w:p></w:hdr>`
+	if err := d.registerContentType(name, HeaderContentType); err != nil {
+		return err
+	}
+	id, err := d.AddRelationship(DocumentXml, HeaderRelationshipType, relationshipTarget(DocumentXml, name), false)
+	if err != nil {
+		return err
+	}
+
+	// the header reference must be the first element of the section properties
+	reference := fmt.Sprintf(`<w:headerReference w:type="default" r:id="%s"/>`, id)
+	document := sectionPropertiesOpenTagRegex.ReplaceAllFunc(d.files[DocumentXml], func(tag []byte) []byte {
+		match := sectionPropertiesOpenTagRegex.FindSubmatch(tag)
+		if len(match[2]) > 0 {
+			return []byte(fmt.Sprintf("<w:sectPr%s>%s</w:sectPr>", match[1], reference))
+		}
+		return []byte(fmt.Sprintf("<w:sectPr%s>%s", match[1], reference))
+	})
+	d.files[DocumentXml] = document
+	if err := d.parseFile(DocumentXml); err != nil {
+		return err
+	}
+
+	d.files[name] = []byte(header)
+	d.headerFiles = append(d.headerFiles, name)
+	return d.parseFile(name)
+}
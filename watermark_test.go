@@ -0,0 +1,110 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestDocument_SetWatermark(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := doc.SetWatermark("DRAFT", WatermarkOptions{}); err != nil {
+		t.Error("setting watermark failed", err)
+		return
+	}
+	if err := doc.SetWatermark("CONFIDENTIAL", WatermarkOptions{Color: "#FF0000", Opacity: 0.3}); err != nil {
+		t.Error("replacing watermark failed", err)
+		return
+	}
+
+	header := writtenFile(t, doc, "word/header1.xml")
+	if err := xml.Unmarshal(header, new(interface{})); err != nil {
+		t.Errorf("written header is not valid xml: %s", err)
+	}
+	if n := bytes.Count(header, []byte(watermarkShapeID)); n != 1 {
+		t.Errorf("unexpected number of watermarks, want=%d, have=%d", 1, n)
+	}
+	for _, expected := range []string{`string="CONFIDENTIAL"`, `fillcolor="#FF0000"`, `<v:fill opacity="0.3"/>`} {
+		if !bytes.Contains(header, []byte(expected)) {
+			t.Errorf("expected header to contain %s", expected)
+		}
+	}
+	if text := writtenText(t, doc, "word/header1.xml"); text != "Header {key}" {
+		t.Errorf("header text was changed, have=%s", text)
+	}
+}
+
+func TestDocument_SetWatermark_WithoutHeader(t *testing.T) {
+	doc, err := OpenBytes(withoutFiles(t, newTestDocx(t, nil), "word/header1.xml", "word/footer1.xml"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := doc.SetWatermark("DRAFT", WatermarkOptions{}); err != nil {
+		t.Error("setting watermark failed", err)
+		return
+	}
+
+	header := writtenFile(t, doc, "word/header1.xml")
+	if err := xml.Unmarshal(header, new(interface{})); err != nil {
+		t.Errorf("written header is not valid xml: %s", err)
+	}
+	if !bytes.Contains(header, []byte(`string="DRAFT"`)) {
+		t.Error("expected header to contain the watermark")
+	}
+
+	rels, err := doc.Relationships(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	for _, rel := range rels {
+		if rel.Type == HeaderRelationshipType && rel.Target == "header1.xml" {
+			id = rel.ID
+		}
+	}
+	if id == "" {
+		t.Fatalf("relationship to the added header is missing, have=%v", rels)
+	}
+	reference := `<w:headerReference w:type="default" r:id="` + id + `"/>`
+	if !strings.Contains(string(writtenFile(t, doc, DocumentXml)), reference) {
+		t.Errorf("expected the section to reference the added header with %s", reference)
+	}
+	if !strings.Contains(string(writtenFile(t, doc, ContentTypesXml)), `<Override PartName="/word/header1.xml" ContentType="`+HeaderContentType+`"/>`) {
+		t.Error("expected the content type of the added header to be declared")
+	}
+}
+
+// withoutFiles returns a copy of the docx archive without the given files.
+func withoutFiles(t testing.TB, docx []byte, names ...string) []byte {
+	archive, err := zip.NewReader(bytes.NewReader(docx), int64(len(docx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, file := range archive.File {
+		removed := false
+		for _, name := range names {
+			removed = removed || file.Name == name
+		}
+		if removed {
+			continue
+		}
+		if err := zipWriter.Copy(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,21 @@
+package docx
+
+import (
+	"bytes"
+)
+
+// XmlDeclaration is the canonical XML declaration of all XML parts. Word expects the parts to be standalone.
+const XmlDeclaration = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`
+
+// withXmlDeclaration prepends the XmlDeclaration to the data of an XML part, unless it already has a declaration.
+// Other parts (e.g. media files) and empty parts are returned unchanged.
+func withXmlDeclaration(name string, data []byte) []byte {
+	if !isXmlPart(name) {
+		return data
+	}
+	content := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\ufeff")), " \t\r\n")
+	if len(content) == 0 || bytes.HasPrefix(content, []byte("<?xml")) {
+		return data
+	}
+	return append([]byte(XmlDeclaration+"\n"), content...)
+}
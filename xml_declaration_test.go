@@ -0,0 +1,40 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithXmlDeclaration(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "word/document.xml", data: `<w:document/>`, expected: XmlDeclaration + "\n<w:document/>"},
+		{name: "word/document.xml", data: "\ufeff\n  <w:document/>", expected: XmlDeclaration + "\n<w:document/>"},
+		{name: "word/_rels/document.xml.rels", data: `<Relationships/>`, expected: XmlDeclaration + "\n<Relationships/>"},
+		{name: "word/document.xml", data: `<?xml version="1.0"?><w:document/>`, expected: `<?xml version="1.0"?><w:document/>`},
+		{name: "word/document.xml", data: "", expected: ""},
+		{name: "word/media/image1.png", data: "<binary", expected: "<binary"},
+	}
+
+	for _, tt := range tests {
+		if data := string(withXmlDeclaration(tt.name, []byte(tt.data))); data != tt.expected {
+			t.Errorf("unexpected data of %s, want=%q, have=%q", tt.name, tt.expected, data)
+		}
+	}
+}
+
+func TestDocument_WriteXmlDeclaration(t *testing.T) {
+	doc, err := OpenBytes(newTestDocx(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetFile(DocumentXml, []byte(newTestDocumentXml("")[len(XmlDeclaration):])); err != nil {
+		t.Fatal(err)
+	}
+	if written := string(writtenFile(t, doc, DocumentXml)); !strings.HasPrefix(written, XmlDeclaration) {
+		t.Errorf("modified part has no declaration, have=%s", written)
+	}
+}